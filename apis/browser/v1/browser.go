@@ -12,7 +12,9 @@ import (
 // +kubebuilder:printcolumn:name="Browser",type="string",JSONPath=".spec.browserName"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.browserVersion"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
 // +kubebuilder:printcolumn:name="PodIP",type="string",JSONPath=".status.podIP"
+// +kubebuilder:printcolumn:name="Queue",type="integer",JSONPath=".status.queuePosition",priority=1
 // +kubebuilder:printcolumn:name="StartTime",type="date",JSONPath=".status.startTime"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:path=browsers,scope=Namespaced,shortName=brw
@@ -38,6 +40,23 @@ type BrowserSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	BrowserVersion string `json:"browserVersion"`
+
+	// MaxLifetime limits how long the Browser may exist, measured from its admission by the namespace quota.
+	// Expired Browsers are deleted by the controller. Defaults to the BrowserConfig maxLifetime
+	// +optional
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+
+	// TTLSecondsAfterFinished limits how long a Failed Browser is kept before it is deleted.
+	// Defaults to the BrowserConfig ttlSecondsAfterFinished
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Priority orders Browsers queued by the BrowserConfig quota, higher values are admitted first.
+	// Browsers of the same priority are admitted fairly across selenosis.io/owner labels. Defaults to 0,
+	// may not exceed the quota maxPriority
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
 }
 
 // BrowserStatus defines the observed state of BrowserPod
@@ -46,6 +65,23 @@ type BrowserStatus struct {
 	// +optional
 	PodIP string `json:"podIP,omitempty"`
 
+	// ResolvedVersion is the concrete BrowserConfig version selected for spec.browserVersion,
+	// e.g. 120.0 for latest or 120
+	// +optional
+	ResolvedVersion string `json:"resolvedVersion,omitempty"`
+
+	// PodName is the name of the browser pod, differs from the Browser name when the pod was claimed from a warm pool
+	// +optional
+	PodName string `json:"podName,omitempty"`
+
+	// ServiceName is the name of the Service created for the Browser when BrowserConfig service is set
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// DNSName is the in-cluster DNS name of the Browser Service, e.g. <serviceName>.<namespace>.svc
+	// +optional
+	DNSName string `json:"dnsName,omitempty"`
+
 	// Phase is the current lifecycle phase of the pod
 	// +optional
 	Phase corev1.PodPhase `json:"phase,omitempty"`
@@ -63,10 +99,32 @@ type BrowserStatus struct {
 	// +optional
 	StartTime *metav1.Time `json:"startTime,omitempty"`
 
+	// QueuePosition is the 1-based position of the Browser in the admission queue of its namespace,
+	// unset once admitted
+	// +optional
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+
+	// CompletionTime is when the controller observed the Browser as finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
 	// ContainerStatuses provides detailed status information about each container
 	// +optional
 	// +listType=atomic
 	ContainerStatuses []ContainerStatus `json:"containerStatuses,omitempty"`
+
+	// ObservedGeneration is the most recent Browser generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the Browser state
+	// (Scheduled, ImagePulled, Ready, Terminating)
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 // ContainerStatus represents the status of a container
@@ -0,0 +1,49 @@
+package v1
+
+// Condition types reported in BrowserStatus.Conditions.
+const (
+	// BrowserScheduled indicates the browser pod has been bound to a node.
+	BrowserScheduled = "Scheduled"
+
+	// BrowserImagePulled indicates all container images of the browser pod are present on the node.
+	BrowserImagePulled = "ImagePulled"
+
+	// BrowserReady indicates the browser pod is running and able to serve sessions.
+	BrowserReady = "Ready"
+
+	// BrowserTerminating indicates the Browser is being torn down.
+	BrowserTerminating = "Terminating"
+
+	// BrowserResources reports how the resource overrides of the selenosis options were applied.
+	BrowserResources = "Resources"
+
+	// BrowserAdmitted indicates the Browser fits the BrowserConfig quota of its namespace, queued Browsers report False.
+	BrowserAdmitted = "Admitted"
+)
+
+// Condition reasons set by the browser-controller.
+const (
+	ReasonPodPending          = "PodPending"
+	ReasonPodScheduled        = "PodScheduled"
+	ReasonPodNotScheduled     = "PodNotScheduled"
+	ReasonPodReady            = "PodReady"
+	ReasonPodNotReady         = "PodNotReady"
+	ReasonPodFailed           = "PodFailed"
+	ReasonPodDeleted          = "PodDeleted"
+	ReasonImagesPulled        = "ImagesPulled"
+	ReasonImagesPulling       = "ImagesPulling"
+	ReasonConfigNotFound      = "ConfigNotFound"
+	ReasonInvalidOptions      = "InvalidSelenosisOptions"
+	ReasonCreationTimeout     = "CreationTimeout"
+	ReasonContainerFailed     = "ContainerFailed"
+	ReasonContainerTerminated = "ContainerTerminated"
+	ReasonBrowserDeleted      = "BrowserDeleted"
+	ReasonMaxLifetimeExceeded = "MaxLifetimeExceeded"
+	ReasonTTLExpired          = "TTLAfterFinishedExpired"
+	ReasonResourcesApplied    = "ResourcesApplied"
+	ReasonResourcesClamped    = "ResourcesClamped"
+	ReasonResourcesRejected   = "ResourcesRejected"
+	ReasonOptionsDenied       = "OptionsDenied"
+	ReasonQueued              = "Queued"
+	ReasonAdmitted            = "Admitted"
+)
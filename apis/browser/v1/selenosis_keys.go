@@ -1,6 +1,13 @@
 package v1
 
 var (
-	SelenosisOptionsAnnotationKey = "selenosis.io/options"
-	SelenosisOwnerLabelKey        = "selenosis.io/owner"
+	SelenosisOptionsAnnotationKey   = "selenosis.io/options"
+	SelenosisOwnerLabelKey          = "selenosis.io/owner"
+	SelenosisPoolLabelKey           = "selenosis.io/pool"
+	SelenosisBrowserLabelKey        = "selenosis.io/browser"
+	SelenosisBrowserNameLabelKey    = "selenosis.io/browser.name"
+	SelenosisBrowserVersionLabelKey = "selenosis.io/browser.version"
+	SelenosisNetworkPolicyLabelKey  = "selenosis.io/network-policy"
+
+	SelenosisCriticalContainersAnnotationKey = "selenosis.io/critical-containers"
 )
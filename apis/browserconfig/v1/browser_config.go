@@ -2,12 +2,19 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // BrowserConfig is the root CRD type that defines browser configurations and associated pod templates.
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type==\"Valid\")].status"
+// +kubebuilder:printcolumn:name="Conflicting",type="string",JSONPath=".status.conditions[?(@.type==\"Conflicting\")].status"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type BrowserConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -28,6 +35,37 @@ type BrowserConfigSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinProperties=1
 	Browsers map[string]map[string]*BrowserVersionConfigSpec `json:"browsers"`
+
+	// Quota caps the concurrent Browsers of the namespace. When several BrowserConfigs
+	// of a namespace define a quota, the lowest limit of each kind applies.
+	// +optional
+	Quota *BrowserQuota `json:"quota,omitempty"`
+}
+
+// BrowserQuota caps concurrent Browsers. Browsers over a limit are queued as Pending
+// and admitted by Browser priority, fairly across owners, as capacity frees up.
+type BrowserQuota struct {
+	// MaxBrowsers caps the Browsers of the namespace.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxBrowsers *int32 `json:"maxBrowsers,omitempty"`
+
+	// MaxBrowsersPerVersion caps the Browsers of each browser:version.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxBrowsersPerVersion *int32 `json:"maxBrowsersPerVersion,omitempty"`
+
+	// MaxBrowsersPerOwner caps the Browsers sharing a selenosis.io/owner label,
+	// Browsers without the label are only bound by the other limits.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxBrowsersPerOwner *int32 `json:"maxBrowsersPerOwner,omitempty"`
+
+	// MaxPriority is the highest Browser spec.priority of the namespace, Browsers asking for
+	// more are rejected. Defaults to 0, so priorities only lower the place in the queue.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxPriority *int32 `json:"maxPriority,omitempty"`
 }
 
 // Template defines a base pod specification that applies to all browsers/versions unless overridden.
@@ -42,6 +80,28 @@ type Template struct {
 	// Env defines environment variables for the main container.
 	Env *[]corev1.EnvVar `json:"env,omitempty"`
 
+	// EnvFrom defines sources to populate environment variables of the main container.
+	EnvFrom *[]corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Args are arguments to the main container entrypoint.
+	Args *[]string `json:"args,omitempty"`
+
+	// ReadinessProbe defines the readiness probe of the main container.
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe defines the liveness probe of the main container.
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// StartupProbe defines the startup probe of the main container.
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// Lifecycle defines lifecycle hooks of the main container, e.g. preStop.
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
+
+	// ContainerSecurityContext defines security context for the main container.
+	// Privileged is applied on top of it.
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
 	// Resources defines CPU/memory requests and limits for the main container.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
@@ -66,6 +126,28 @@ type Template struct {
 	// HostAliases defines custom /etc/hosts entries.
 	HostAliases *[]corev1.HostAlias `json:"hostAliases,omitempty"`
 
+	// TopologySpreadConstraints spread browser pods across topology domains, e.g. zones.
+	TopologySpreadConstraints *[]corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// PriorityClassName defines the priority of browser pods, e.g. a preemptible class for CI sessions.
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// RuntimeClassName selects the container runtime of browser pods, e.g. gVisor or Kata.
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// ServiceAccountName is the service account browser pods run as.
+	ServiceAccountName *string `json:"serviceAccountName,omitempty"`
+
+	// TerminationGracePeriodSeconds is the time browser pods are given to stop gracefully.
+	// +kubebuilder:validation:Minimum=0
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// ShareProcessNamespace shares a single process namespace between the pod containers.
+	ShareProcessNamespace *bool `json:"shareProcessNamespace,omitempty"`
+
+	// EnableServiceLinks injects environment variables for the Services of the namespace.
+	EnableServiceLinks *bool `json:"enableServiceLinks,omitempty"`
+
 	// List of initialization containers belonging to the pod.
 	InitContainers *[]Sidecar `json:"initContainers,omitempty"`
 
@@ -89,6 +171,193 @@ type Template struct {
 	// Container's working directory.
 	// +optional
 	WorkingDir *string `json:"workingDir,omitempty"`
+
+	// StartupTimeout limits how long the browser pod may stay Pending before the Browser is failed.
+	// Overrides the controller --pod-creation-timeout flag.
+	// +optional
+	StartupTimeout *metav1.Duration `json:"startupTimeout,omitempty"`
+
+	// DeletionTimeout limits how long the controller waits for graceful pod deletion before forcing it.
+	// Overrides the controller --pod-deletion-timeout flag.
+	// +optional
+	DeletionTimeout *metav1.Duration `json:"deletionTimeout,omitempty"`
+
+	// MaxLifetime is the default upper bound on Browser lifetime, used when spec.maxLifetime is not set.
+	// +optional
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+
+	// TTLSecondsAfterFinished is the default time a Failed Browser is kept before deletion,
+	// used when spec.ttlSecondsAfterFinished is not set.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Pool keeps pre-provisioned idle browser pods that new Browsers claim instead of creating a pod.
+	// +optional
+	Pool *Pool `json:"pool,omitempty"`
+
+	// Service creates a Service owned by each Browser exposing the ports of its pod containers.
+	// +optional
+	Service *BrowserService `json:"service,omitempty"`
+
+	// NetworkPolicy restricts the network access of browser pods.
+	// +optional
+	NetworkPolicy *BrowserNetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// ResourcePolicy allows Browsers to override container resources through the selenosis.io/options
+	// annotation within bounds. Resource overrides are rejected when unset.
+	// +optional
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"`
+
+	// OptionsPolicy restricts the containers, env vars and labels Browsers may override through
+	// the selenosis.io/options annotation. Everything but the controller labels may be overridden when unset.
+	// +optional
+	OptionsPolicy *OptionsPolicy `json:"optionsPolicy,omitempty"`
+}
+
+// OptionsPolicy restricts what Browsers may override through the selenosis.io/options annotation.
+// Entries ending with * match by prefix, denied entries win over allowed ones.
+type OptionsPolicy struct {
+	// Containers lists the containers whose env and resources may be overridden, any container when empty.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+
+	// AllowedEnv lists the env var names that may be overridden, any name when empty.
+	// +optional
+	AllowedEnv []string `json:"allowedEnv,omitempty"`
+
+	// DeniedEnv lists the env var names that may not be overridden.
+	// +optional
+	DeniedEnv []string `json:"deniedEnv,omitempty"`
+
+	// AllowedLabels lists the pod label keys that may be set, any key when empty.
+	// +optional
+	AllowedLabels []string `json:"allowedLabels,omitempty"`
+
+	// DeniedLabels lists the pod label keys that may not be set.
+	// +optional
+	DeniedLabels []string `json:"deniedLabels,omitempty"`
+}
+
+// ResourcePolicyAction selects how resource overrides outside the policy bounds are handled.
+// +kubebuilder:validation:Enum=Clamp;Reject
+type ResourcePolicyAction string
+
+const (
+	// ResourcePolicyClamp adjusts out-of-bounds values to the nearest bound
+	ResourcePolicyClamp ResourcePolicyAction = "Clamp"
+	// ResourcePolicyReject fails Browsers requesting out-of-bounds values
+	ResourcePolicyReject ResourcePolicyAction = "Reject"
+)
+
+// ResourcePolicy bounds the container resource requests and limits Browsers may set through selenosis options.
+type ResourcePolicy struct {
+	// Min is the lowest request or limit a container may set per resource.
+	// +optional
+	Min corev1.ResourceList `json:"min,omitempty"`
+
+	// Max is the highest request or limit a container may set per resource.
+	// Resources without min, max or allowed values can't be overridden.
+	// +optional
+	Max corev1.ResourceList `json:"max,omitempty"`
+
+	// Allowed restricts the requests and limits of a resource to a set of values, e.g. memory of 1Gi, 2Gi or 4Gi.
+	// Clamp rounds a value up to the next allowed one, down to the largest when it exceeds them all.
+	// +optional
+	Allowed map[corev1.ResourceName][]resource.Quantity `json:"allowed,omitempty"`
+
+	// Action clamps out-of-bounds values or rejects the Browser.
+	// +optional
+	// +kubebuilder:default=Clamp
+	Action ResourcePolicyAction `json:"action,omitempty"`
+}
+
+// NetworkPolicyScope selects which Browsers share a NetworkPolicy.
+// +kubebuilder:validation:Enum=Browser;Shared
+type NetworkPolicyScope string
+
+const (
+	// NetworkPolicyScopeBrowser creates a NetworkPolicy per Browser
+	NetworkPolicyScopeBrowser NetworkPolicyScope = "Browser"
+	// NetworkPolicyScopeShared creates one NetworkPolicy per browser version shared by its Browsers
+	NetworkPolicyScopeShared NetworkPolicyScope = "Shared"
+)
+
+// BrowserNetworkPolicy defines the NetworkPolicy created for browser pods.
+type BrowserNetworkPolicy struct {
+	// Scope creates a NetworkPolicy per Browser or one shared by all Browsers of a browser version.
+	// The policy is removed once no Browser uses it.
+	// +optional
+	// +kubebuilder:default=Browser
+	Scope NetworkPolicyScope `json:"scope,omitempty"`
+
+	// IngressFrom lists the peers allowed to connect to browser pods, e.g. the selenosis router pods.
+	// Ingress is not restricted when unset, an empty list denies all ingress.
+	// +optional
+	IngressFrom *[]networkingv1.NetworkPolicyPeer `json:"ingressFrom,omitempty"`
+
+	// Egress lists the destinations browser pods may connect to.
+	// Egress is not restricted when unset, an empty list denies all egress except DNS.
+	// +optional
+	Egress *[]BrowserEgressRule `json:"egress,omitempty"`
+
+	// AllowDNS allows egress to port 53 when egress is restricted. Defaults to true.
+	// +optional
+	AllowDNS *bool `json:"allowDNS,omitempty"`
+}
+
+// BrowserEgressRule allows egress to CIDRs on ports.
+type BrowserEgressRule struct {
+	// CIDRs are the allowed destination IP ranges, any destination when empty.
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+
+	// Ports are the allowed destination ports, any port when empty.
+	// +optional
+	Ports []networkingv1.NetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// BrowserServiceType selects how the per-Browser Service is exposed.
+// +kubebuilder:validation:Enum=Headless;ClusterIP
+type BrowserServiceType string
+
+const (
+	// BrowserServiceHeadless creates a Service without cluster IP, its DNS name resolves to the pod IP
+	BrowserServiceHeadless BrowserServiceType = "Headless"
+	// BrowserServiceClusterIP creates a Service with a virtual cluster IP
+	BrowserServiceClusterIP BrowserServiceType = "ClusterIP"
+)
+
+// BrowserService defines the Service created for each Browser.
+type BrowserService struct {
+	// Type of the Service, Headless or ClusterIP.
+	// +optional
+	// +kubebuilder:default=Headless
+	Type BrowserServiceType `json:"type,omitempty"`
+
+	// Labels are additional Service labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are additional Service annotations, e.g. for service mesh integration.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Pool defines a warm pool of idle browser pods for a browser version.
+type Pool struct {
+	// MinIdle is the number of idle pods the controller keeps ready.
+	// +kubebuilder:validation:Minimum=0
+	MinIdle int32 `json:"minIdle"`
+
+	// MaxIdle caps the number of idle pods, surplus pods are deleted. Defaults to MinIdle.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxIdle *int32 `json:"maxIdle,omitempty"`
+
+	// IdleTTL recycles idle pods older than this duration.
+	// +optional
+	IdleTTL *metav1.Duration `json:"idleTTL,omitempty"`
 }
 
 // Sidecar defines a secondary container to be injected into the pod.
@@ -102,6 +371,9 @@ type Sidecar struct {
 	// Command overrides the container entrypoint.Template.
 	Command *[]string `json:"command,omitempty"`
 
+	// Args are arguments to the container entrypoint.
+	Args *[]string `json:"args,omitempty"`
+
 	// Container's working directory.
 	WorkingDir *string `json:"workingDir,omitempty"`
 
@@ -111,6 +383,9 @@ type Sidecar struct {
 	// Env defines environment variables for the sidecar.
 	Env *[]corev1.EnvVar `json:"env,omitempty"`
 
+	// EnvFrom defines sources to populate environment variables of the sidecar.
+	EnvFrom *[]corev1.EnvFromSource `json:"envFrom,omitempty"`
+
 	// VolumeMounts defines mounts for pod volumes.
 	VolumeMounts *[]corev1.VolumeMount `json:"volumeMounts,omitempty"`
 
@@ -119,6 +394,38 @@ type Sidecar struct {
 
 	// Resources defines CPU/memory requests and limits for the sidecar container.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ReadinessProbe defines the readiness probe of the sidecar.
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe defines the liveness probe of the sidecar.
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// StartupProbe defines the startup probe of the sidecar.
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// Lifecycle defines lifecycle hooks of the sidecar, e.g. preStop.
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
+
+	// SecurityContext defines security context for the sidecar container.
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Critical fails and deletes the Browser when the container terminates.
+	// Defaults to true for the seleniferous sidecar and false for other containers.
+	// Classic init containers run to completion and cannot be critical.
+	// +optional
+	Critical *bool `json:"critical,omitempty"`
+
+	// RestartPolicy Always turns an init container into a native sidecar that starts before
+	// the browser and keeps running alongside it. Only allowed on initContainers.
+	// +optional
+	// +kubebuilder:validation:Enum=Always
+	RestartPolicy *corev1.ContainerRestartPolicy `json:"restartPolicy,omitempty"`
+}
+
+// IsNativeSidecar reports whether the container is an init container running for the pod lifetime.
+func (s *Sidecar) IsNativeSidecar() bool {
+	return s.RestartPolicy != nil && *s.RestartPolicy == corev1.ContainerRestartPolicyAlways
 }
 
 // BrowserVersionConfigSpec defines per-browser-version overrides.
@@ -127,33 +434,85 @@ type BrowserVersionConfigSpec struct {
 	// Image is the browser container image.
 	Image string `json:"image"`
 
-	Labels           *map[string]string             `json:"labels,omitempty"`
-	Annotations      *map[string]string             `json:"annotations,omitempty"`
-	Env              *[]corev1.EnvVar               `json:"env,omitempty"`
-	Resources        *corev1.ResourceRequirements   `json:"resources,omitempty"`
-	ImagePullPolicy  corev1.PullPolicy              `json:"imagePullPolicy,omitempty"`
-	Volumes          *[]corev1.Volume               `json:"volumes,omitempty"`
-	VolumeMounts     *[]corev1.VolumeMount          `json:"volumeMounts,omitempty"`
-	NodeSelector     *map[string]string             `json:"nodeSelector,omitempty"`
-	Affinity         *corev1.Affinity               `json:"affinity,omitempty"`
-	Tolerations      *[]corev1.Toleration           `json:"tolerations,omitempty"`
-	HostAliases      *[]corev1.HostAlias            `json:"hostAliases,omitempty"`
-	InitContainers   *[]Sidecar                     `json:"initContainers,omitempty"`
-	Sidecars         *[]Sidecar                     `json:"sidecars,omitempty"`
-	Privileged       *bool                          `json:"privileged,omitempty"`
-	ImagePullSecrets *[]corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
-	DNSConfig        *corev1.PodDNSConfig           `json:"dnsConfig,omitempty"`
-	SecurityContext  *corev1.PodSecurityContext     `json:"securityContext,omitempty"`
-	WorkingDir       *string                        `json:"workingDir,omitempty"`
+	Labels                        *map[string]string                 `json:"labels,omitempty"`
+	Annotations                   *map[string]string                 `json:"annotations,omitempty"`
+	Env                           *[]corev1.EnvVar                   `json:"env,omitempty"`
+	EnvFrom                       *[]corev1.EnvFromSource            `json:"envFrom,omitempty"`
+	Args                          *[]string                          `json:"args,omitempty"`
+	ReadinessProbe                *corev1.Probe                      `json:"readinessProbe,omitempty"`
+	LivenessProbe                 *corev1.Probe                      `json:"livenessProbe,omitempty"`
+	StartupProbe                  *corev1.Probe                      `json:"startupProbe,omitempty"`
+	Lifecycle                     *corev1.Lifecycle                  `json:"lifecycle,omitempty"`
+	ContainerSecurityContext      *corev1.SecurityContext            `json:"containerSecurityContext,omitempty"`
+	Resources                     *corev1.ResourceRequirements       `json:"resources,omitempty"`
+	ImagePullPolicy               corev1.PullPolicy                  `json:"imagePullPolicy,omitempty"`
+	Volumes                       *[]corev1.Volume                   `json:"volumes,omitempty"`
+	VolumeMounts                  *[]corev1.VolumeMount              `json:"volumeMounts,omitempty"`
+	NodeSelector                  *map[string]string                 `json:"nodeSelector,omitempty"`
+	Affinity                      *corev1.Affinity                   `json:"affinity,omitempty"`
+	Tolerations                   *[]corev1.Toleration               `json:"tolerations,omitempty"`
+	HostAliases                   *[]corev1.HostAlias                `json:"hostAliases,omitempty"`
+	TopologySpreadConstraints     *[]corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	PriorityClassName             *string                            `json:"priorityClassName,omitempty"`
+	RuntimeClassName              *string                            `json:"runtimeClassName,omitempty"`
+	ServiceAccountName            *string                            `json:"serviceAccountName,omitempty"`
+	TerminationGracePeriodSeconds *int64                             `json:"terminationGracePeriodSeconds,omitempty"`
+	ShareProcessNamespace         *bool                              `json:"shareProcessNamespace,omitempty"`
+	EnableServiceLinks            *bool                              `json:"enableServiceLinks,omitempty"`
+	InitContainers                *[]Sidecar                         `json:"initContainers,omitempty"`
+	Sidecars                      *[]Sidecar                         `json:"sidecars,omitempty"`
+	Privileged                    *bool                              `json:"privileged,omitempty"`
+	ImagePullSecrets              *[]corev1.LocalObjectReference     `json:"imagePullSecrets,omitempty"`
+	DNSConfig                     *corev1.PodDNSConfig               `json:"dnsConfig,omitempty"`
+	SecurityContext               *corev1.PodSecurityContext         `json:"securityContext,omitempty"`
+	WorkingDir                    *string                            `json:"workingDir,omitempty"`
+	StartupTimeout                *metav1.Duration                   `json:"startupTimeout,omitempty"`
+	DeletionTimeout               *metav1.Duration                   `json:"deletionTimeout,omitempty"`
+	MaxLifetime                   *metav1.Duration                   `json:"maxLifetime,omitempty"`
+	TTLSecondsAfterFinished       *int32                             `json:"ttlSecondsAfterFinished,omitempty"`
+	Pool                          *Pool                              `json:"pool,omitempty"`
+	Service                       *BrowserService                    `json:"service,omitempty"`
+	NetworkPolicy                 *BrowserNetworkPolicy              `json:"networkPolicy,omitempty"`
+	ResourcePolicy                *ResourcePolicy                    `json:"resourcePolicy,omitempty"`
+	OptionsPolicy                 *OptionsPolicy                     `json:"optionsPolicy,omitempty"`
 }
 
 // ConfigStatus defines the observed state of BrowserConfig.
 type ConfigStatus struct {
-	// Version is the current configuration version.
+	// Version is the current configuration version, a content hash of the merged spec.
 	Version string `json:"version,omitempty"`
 
 	// LastUpdated is the timestamp of the last update.
 	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+
+	// ObservedGeneration is the most recent generation processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Browsers lists the browser versions loaded from this BrowserConfig with their images.
+	// +optional
+	// +listType=atomic
+	Browsers []BrowserVersionStatus `json:"browsers,omitempty"`
+
+	// Conditions describe the validation result, see BrowserConfigValid and BrowserConfigConflicting.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// BrowserVersionStatus describes a browser version resolved from the merged spec.
+type BrowserVersionStatus struct {
+	// Name is the browser name.
+	Name string `json:"name"`
+
+	// Version is the browser version.
+	Version string `json:"version"`
+
+	// Image is the browser container image.
+	Image string `json:"image"`
 }
 
 // BrowserConfigList contains a list of BrowserConfig objects.
@@ -188,6 +547,32 @@ func (b *BrowserVersionConfigSpec) mergeWithSpec(t *BrowserConfigSpec) {
 	b.Labels = mergeMapPtr(t.Template.Labels, b.Labels)
 	b.Annotations = mergeMapPtr(t.Template.Annotations, b.Annotations)
 	b.Env = mergeEnvPtr(t.Template.Env, b.Env)
+	b.EnvFrom = mergeEnvFromPtr(t.Template.EnvFrom, b.EnvFrom)
+
+	if b.Args == nil {
+		b.Args = t.Template.Args
+	}
+
+	if b.ReadinessProbe == nil {
+		b.ReadinessProbe = t.Template.ReadinessProbe
+	}
+
+	if b.LivenessProbe == nil {
+		b.LivenessProbe = t.Template.LivenessProbe
+	}
+
+	if b.StartupProbe == nil {
+		b.StartupProbe = t.Template.StartupProbe
+	}
+
+	if b.Lifecycle == nil {
+		b.Lifecycle = t.Template.Lifecycle
+	}
+
+	if b.ContainerSecurityContext == nil {
+		b.ContainerSecurityContext = t.Template.ContainerSecurityContext
+	}
+
 	b.Resources = firstNonNilResource(t.Template.Resources, b.Resources)
 	if b.ImagePullPolicy == "" {
 		b.ImagePullPolicy = t.Template.ImagePullPolicy
@@ -202,6 +587,31 @@ func (b *BrowserVersionConfigSpec) mergeWithSpec(t *BrowserConfigSpec) {
 
 	b.Tolerations = mergeTolerationPtr(t.Template.Tolerations, b.Tolerations)
 	b.HostAliases = mergeHostAliasPtr(t.Template.HostAliases, b.HostAliases)
+	b.TopologySpreadConstraints = mergeTopologySpreadConstraintPtr(t.Template.TopologySpreadConstraints, b.TopologySpreadConstraints)
+
+	if b.PriorityClassName == nil {
+		b.PriorityClassName = t.Template.PriorityClassName
+	}
+
+	if b.RuntimeClassName == nil {
+		b.RuntimeClassName = t.Template.RuntimeClassName
+	}
+
+	if b.ServiceAccountName == nil {
+		b.ServiceAccountName = t.Template.ServiceAccountName
+	}
+
+	if b.TerminationGracePeriodSeconds == nil {
+		b.TerminationGracePeriodSeconds = t.Template.TerminationGracePeriodSeconds
+	}
+
+	if b.ShareProcessNamespace == nil {
+		b.ShareProcessNamespace = t.Template.ShareProcessNamespace
+	}
+
+	if b.EnableServiceLinks == nil {
+		b.EnableServiceLinks = t.Template.EnableServiceLinks
+	}
 	b.VolumeMounts = mergeVolumeMountsPtr(b.VolumeMounts, t.Template.VolumeMounts)
 
 	originalSidecars := b.Sidecars
@@ -257,6 +667,42 @@ func (b *BrowserVersionConfigSpec) mergeWithSpec(t *BrowserConfigSpec) {
 	if b.WorkingDir == nil {
 		b.WorkingDir = t.Template.WorkingDir
 	}
+
+	if b.StartupTimeout == nil {
+		b.StartupTimeout = t.Template.StartupTimeout
+	}
+
+	if b.DeletionTimeout == nil {
+		b.DeletionTimeout = t.Template.DeletionTimeout
+	}
+
+	if b.MaxLifetime == nil {
+		b.MaxLifetime = t.Template.MaxLifetime
+	}
+
+	if b.TTLSecondsAfterFinished == nil {
+		b.TTLSecondsAfterFinished = t.Template.TTLSecondsAfterFinished
+	}
+
+	if b.Pool == nil {
+		b.Pool = t.Template.Pool
+	}
+
+	if b.Service == nil {
+		b.Service = t.Template.Service
+	}
+
+	if b.NetworkPolicy == nil {
+		b.NetworkPolicy = t.Template.NetworkPolicy
+	}
+
+	if b.ResourcePolicy == nil {
+		b.ResourcePolicy = t.Template.ResourcePolicy
+	}
+
+	if b.OptionsPolicy == nil {
+		b.OptionsPolicy = t.Template.OptionsPolicy
+	}
 }
 
 func mergeMapPtr(template, override *map[string]string) *map[string]string {
@@ -285,23 +731,24 @@ func mergeEnvPtr(template, override *[]corev1.EnvVar) *[]corev1.EnvVar {
 		return nil
 	}
 
-	result := map[string]corev1.EnvVar{}
-	if template != nil {
-		for _, env := range *template {
-			result[env.Name] = env
+	// keep first-seen order so the merged spec is deterministic
+	merged := []corev1.EnvVar{}
+	index := map[string]int{}
+	add := func(envs *[]corev1.EnvVar) {
+		if envs == nil {
+			return
 		}
-	}
-
-	if override != nil {
-		for _, env := range *override {
-			result[env.Name] = env
+		for _, env := range *envs {
+			if i, ok := index[env.Name]; ok {
+				merged[i] = env
+				continue
+			}
+			index[env.Name] = len(merged)
+			merged = append(merged, env)
 		}
 	}
-
-	merged := make([]corev1.EnvVar, 0, len(result))
-	for _, env := range result {
-		merged = append(merged, env)
-	}
+	add(template)
+	add(override)
 
 	return &merged
 }
@@ -365,6 +812,48 @@ func mergeHostAliasPtr(template, override *[]corev1.HostAlias) *[]corev1.HostAli
 	return &result
 }
 
+// mergeTopologySpreadConstraintPtr merges constraints keyed by topologyKey and whenUnsatisfiable,
+// an override replaces the template constraint with the same key. Duplicates within a single list
+// are kept for validation to report, the API server rejects pods with two constraints for the same key.
+func mergeTopologySpreadConstraintPtr(template, override *[]corev1.TopologySpreadConstraint) *[]corev1.TopologySpreadConstraint {
+	if template == nil && override == nil {
+		return nil
+	}
+
+	type key struct {
+		topologyKey       string
+		whenUnsatisfiable corev1.UnsatisfiableConstraintAction
+	}
+
+	merged := []corev1.TopologySpreadConstraint{}
+	index := map[key]int{}
+	if template != nil {
+		for _, c := range *template {
+			k := key{c.TopologyKey, c.WhenUnsatisfiable}
+			if _, ok := index[k]; !ok {
+				index[k] = len(merged)
+			}
+			merged = append(merged, c)
+		}
+	}
+	if override != nil {
+		for _, c := range *override {
+			k := key{c.TopologyKey, c.WhenUnsatisfiable}
+			if i, ok := index[k]; ok {
+				merged[i] = c
+				delete(index, k)
+				continue
+			}
+			merged = append(merged, c)
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+	return &merged
+}
+
 func mergeSidecarPtr(template, override *[]Sidecar) *[]Sidecar {
 	if template == nil && override == nil {
 		return nil
@@ -437,14 +926,65 @@ func (s *Sidecar) mergeWithTemplate(t *Sidecar) {
 		s.Command = t.Command
 	}
 
+	if s.Args == nil {
+		s.Args = t.Args
+	}
+
 	s.WorkingDir = t.WorkingDir
+
 	s.Env = mergeEnvPtr(t.Env, s.Env)
+	s.EnvFrom = mergeEnvFromPtr(t.EnvFrom, s.EnvFrom)
 	s.Ports = mergeContainerPortPtr(t.Ports, s.Ports)
 	s.VolumeMounts = mergeVolumeMountPtr(t.VolumeMounts, s.VolumeMounts)
 
 	if s.Resources == nil {
 		s.Resources = t.Resources
 	}
+
+	if s.ReadinessProbe == nil {
+		s.ReadinessProbe = t.ReadinessProbe
+	}
+
+	if s.LivenessProbe == nil {
+		s.LivenessProbe = t.LivenessProbe
+	}
+
+	if s.StartupProbe == nil {
+		s.StartupProbe = t.StartupProbe
+	}
+
+	if s.Lifecycle == nil {
+		s.Lifecycle = t.Lifecycle
+	}
+
+	if s.SecurityContext == nil {
+		s.SecurityContext = t.SecurityContext
+	}
+
+	if s.RestartPolicy == nil {
+		s.RestartPolicy = t.RestartPolicy
+	}
+
+	if s.Critical == nil {
+		s.Critical = t.Critical
+	}
+}
+
+func mergeEnvFromPtr(template, override *[]corev1.EnvFromSource) *[]corev1.EnvFromSource {
+	result := []corev1.EnvFromSource{}
+	if template != nil {
+		result = append(result, *template...)
+	}
+
+	if override != nil {
+		result = append(result, *override...)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return &result
 }
 
 func mergeContainerPortPtr(template, override *[]corev1.ContainerPort) *[]corev1.ContainerPort {
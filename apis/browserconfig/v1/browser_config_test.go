@@ -1,9 +1,13 @@
 package v1
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestMergeWithTemplateInheritsImagePullPolicyFromTemplate(t *testing.T) {
@@ -102,6 +106,17 @@ func TestMergeEnvPtrOverrideWinsByName(t *testing.T) {
 	}
 }
 
+func TestMergeEnvPtrKeepsOrder(t *testing.T) {
+	template := []corev1.EnvVar{{Name: "B", Value: "1"}, {Name: "A", Value: "1"}}
+	override := []corev1.EnvVar{{Name: "C", Value: "2"}, {Name: "B", Value: "2"}}
+
+	merged := mergeEnvPtr(&template, &override)
+	want := []corev1.EnvVar{{Name: "B", Value: "2"}, {Name: "A", Value: "1"}, {Name: "C", Value: "2"}}
+	if !reflect.DeepEqual(*merged, want) {
+		t.Fatalf("expected %+v, got %+v", want, *merged)
+	}
+}
+
 func TestMergeSidecarPtrMergesByName(t *testing.T) {
 	template := []Sidecar{
 		{Name: "metrics", Image: "metrics:1.0"},
@@ -493,3 +508,221 @@ func getSidecarByName(sidecars []Sidecar, name string) *Sidecar {
 	}
 	return nil
 }
+
+func TestMergeWithTemplateTimeouts(t *testing.T) {
+	templateStartup := metav1.Duration{Duration: 10 * time.Minute}
+	templateDeletion := metav1.Duration{Duration: time.Minute}
+	versionStartup := metav1.Duration{Duration: 15 * time.Minute}
+	spec := BrowserConfigSpec{
+		Template: &Template{
+			StartupTimeout:  &templateStartup,
+			DeletionTimeout: &templateDeletion,
+		},
+		Browsers: map[string]map[string]*BrowserVersionConfigSpec{
+			"chrome": {
+				"120.0": {Image: "chrome:120"},
+				"121.0": {Image: "chrome:121", StartupTimeout: &versionStartup},
+			},
+		},
+	}
+
+	spec.MergeWithTemplate()
+
+	inherited := spec.Browsers["chrome"]["120.0"]
+	if inherited.StartupTimeout == nil || inherited.StartupTimeout.Duration != 10*time.Minute {
+		t.Fatalf("expected startupTimeout inherited from template, got %v", inherited.StartupTimeout)
+	}
+	if inherited.DeletionTimeout == nil || inherited.DeletionTimeout.Duration != time.Minute {
+		t.Fatalf("expected deletionTimeout inherited from template, got %v", inherited.DeletionTimeout)
+	}
+
+	overridden := spec.Browsers["chrome"]["121.0"]
+	if overridden.StartupTimeout == nil || overridden.StartupTimeout.Duration != 15*time.Minute {
+		t.Fatalf("expected version startupTimeout to win, got %v", overridden.StartupTimeout)
+	}
+}
+
+func TestMergeWithTemplateLifetimeDefaults(t *testing.T) {
+	lifetime := metav1.Duration{Duration: time.Hour}
+	ttl := int32(300)
+	spec := BrowserConfigSpec{
+		Template: &Template{MaxLifetime: &lifetime, TTLSecondsAfterFinished: &ttl},
+		Browsers: map[string]map[string]*BrowserVersionConfigSpec{
+			"chrome": {"120.0": {Image: "chrome:120"}},
+		},
+	}
+
+	spec.MergeWithTemplate()
+
+	cfg := spec.Browsers["chrome"]["120.0"]
+	if cfg.MaxLifetime == nil || cfg.MaxLifetime.Duration != time.Hour {
+		t.Fatalf("expected maxLifetime inherited from template, got %v", cfg.MaxLifetime)
+	}
+	if cfg.TTLSecondsAfterFinished == nil || *cfg.TTLSecondsAfterFinished != 300 {
+		t.Fatalf("expected ttlSecondsAfterFinished inherited from template, got %v", cfg.TTLSecondsAfterFinished)
+	}
+}
+
+func TestMergeWithTemplateContainerSpec(t *testing.T) {
+	templateArgs := []string{"--template"}
+	versionArgs := []string{"--version"}
+	probe := &corev1.Probe{ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(4444)}}}
+	preStop := &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 5}}}
+	runAsUser := int64(1000)
+	secCtx := &corev1.SecurityContext{RunAsUser: &runAsUser}
+	templateEnvFrom := []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "tmpl"}}}}
+	versionEnvFrom := []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "ver"}}}}
+
+	spec := BrowserConfigSpec{
+		Template: &Template{
+			Args:                     &templateArgs,
+			EnvFrom:                  &templateEnvFrom,
+			ReadinessProbe:           probe,
+			LivenessProbe:            probe,
+			StartupProbe:             probe,
+			Lifecycle:                preStop,
+			ContainerSecurityContext: secCtx,
+		},
+		Browsers: map[string]map[string]*BrowserVersionConfigSpec{
+			"chrome": {
+				"120.0": {Image: "chrome:120"},
+				"121.0": {Image: "chrome:121", Args: &versionArgs, EnvFrom: &versionEnvFrom},
+			},
+		},
+	}
+
+	spec.MergeWithTemplate()
+
+	inherited := spec.Browsers["chrome"]["120.0"]
+	if inherited.Args == nil || (*inherited.Args)[0] != "--template" {
+		t.Fatalf("expected args from template, got %v", inherited.Args)
+	}
+	if inherited.ReadinessProbe != probe || inherited.LivenessProbe != probe || inherited.StartupProbe != probe {
+		t.Fatalf("expected probes from template")
+	}
+	if inherited.Lifecycle != preStop || inherited.ContainerSecurityContext != secCtx {
+		t.Fatalf("expected lifecycle and containerSecurityContext from template")
+	}
+
+	overridden := spec.Browsers["chrome"]["121.0"]
+	if overridden.Args == nil || len(*overridden.Args) != 1 || (*overridden.Args)[0] != "--version" {
+		t.Fatalf("expected version args to replace template args, got %v", overridden.Args)
+	}
+	if overridden.EnvFrom == nil || len(*overridden.EnvFrom) != 2 {
+		t.Fatalf("expected envFrom to be merged, got %v", overridden.EnvFrom)
+	}
+}
+
+func TestSidecarMergeWithTemplateContainerSpec(t *testing.T) {
+	templateArgs := []string{"-nolisten", "tcp"}
+	overrideArgs := []string{"-screen"}
+	templateProbe := &corev1.Probe{InitialDelaySeconds: 1}
+	overrideProbe := &corev1.Probe{InitialDelaySeconds: 2}
+	preStop := &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 5}}}
+	secCtx := &corev1.SecurityContext{}
+	critical := true
+	always := corev1.ContainerRestartPolicyAlways
+
+	s := Sidecar{Name: "xvfb", Args: &overrideArgs, ReadinessProbe: overrideProbe}
+	tmpl := Sidecar{
+		Name:            "xvfb",
+		Args:            &templateArgs,
+		ReadinessProbe:  templateProbe,
+		LivenessProbe:   templateProbe,
+		StartupProbe:    templateProbe,
+		Lifecycle:       preStop,
+		SecurityContext: secCtx,
+		Critical:        &critical,
+		RestartPolicy:   &always,
+	}
+
+	s.mergeWithTemplate(&tmpl)
+
+	if len(*s.Args) != 1 || (*s.Args)[0] != "-screen" {
+		t.Fatalf("expected sidecar args to win, got %v", *s.Args)
+	}
+	if s.ReadinessProbe != overrideProbe || s.LivenessProbe != templateProbe || s.StartupProbe != templateProbe {
+		t.Fatalf("expected probes merged field by field")
+	}
+	if s.Lifecycle != preStop || s.SecurityContext != secCtx {
+		t.Fatalf("expected lifecycle and securityContext from template")
+	}
+	if s.Critical != &critical || !s.IsNativeSidecar() {
+		t.Fatalf("expected critical and restartPolicy from template")
+	}
+}
+
+func TestMergeWithTemplateSchedulingFields(t *testing.T) {
+	zoneSpread := corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}
+	hostSpread := corev1.TopologySpreadConstraint{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname"}
+	grace := int64(30)
+	versionGrace := int64(5)
+	enabled := true
+	spec := BrowserConfigSpec{
+		Template: &Template{
+			TopologySpreadConstraints:     &[]corev1.TopologySpreadConstraint{zoneSpread},
+			PriorityClassName:             strPtr("ci-preemptible"),
+			RuntimeClassName:              strPtr("gvisor"),
+			ServiceAccountName:            strPtr("browsers"),
+			TerminationGracePeriodSeconds: &grace,
+			ShareProcessNamespace:         &enabled,
+			EnableServiceLinks:            &enabled,
+		},
+		Browsers: map[string]map[string]*BrowserVersionConfigSpec{
+			"chrome": {
+				"120.0": {Image: "chrome:120"},
+				"121.0": {
+					Image:                         "chrome:121",
+					TopologySpreadConstraints:     &[]corev1.TopologySpreadConstraint{hostSpread},
+					RuntimeClassName:              strPtr("kata"),
+					TerminationGracePeriodSeconds: &versionGrace,
+				},
+			},
+		},
+	}
+
+	spec.MergeWithTemplate()
+
+	inherited := spec.Browsers["chrome"]["120.0"]
+	if inherited.PriorityClassName == nil || *inherited.PriorityClassName != "ci-preemptible" ||
+		inherited.RuntimeClassName == nil || *inherited.RuntimeClassName != "gvisor" ||
+		inherited.ServiceAccountName == nil || *inherited.ServiceAccountName != "browsers" {
+		t.Fatalf("expected scheduling fields from template, got %+v", inherited)
+	}
+	if inherited.TerminationGracePeriodSeconds != &grace || inherited.ShareProcessNamespace != &enabled || inherited.EnableServiceLinks != &enabled {
+		t.Fatalf("expected pod fields from template, got %+v", inherited)
+	}
+
+	overridden := spec.Browsers["chrome"]["121.0"]
+	if *overridden.RuntimeClassName != "kata" || *overridden.TerminationGracePeriodSeconds != 5 {
+		t.Fatalf("expected version fields to win, got %+v", overridden)
+	}
+	if overridden.TopologySpreadConstraints == nil || len(*overridden.TopologySpreadConstraints) != 2 {
+		t.Fatalf("expected topologySpreadConstraints to be merged, got %+v", overridden.TopologySpreadConstraints)
+	}
+}
+
+func TestMergeTopologySpreadConstraintPtrOverridesSameKey(t *testing.T) {
+	template := &[]corev1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+		{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+	}
+	override := &[]corev1.TopologySpreadConstraint{
+		{MaxSkew: 3, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+		{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.DoNotSchedule},
+	}
+
+	got := mergeTopologySpreadConstraintPtr(template, override)
+	if got == nil || len(*got) != 3 {
+		t.Fatalf("expected 3 constraints, got %+v", got)
+	}
+	if (*got)[0].TopologyKey != "topology.kubernetes.io/zone" || (*got)[0].MaxSkew != 3 {
+		t.Fatalf("expected override to replace the template constraint in place, got %+v", (*got)[0])
+	}
+	if (*got)[1].WhenUnsatisfiable != corev1.ScheduleAnyway || (*got)[2].WhenUnsatisfiable != corev1.DoNotSchedule {
+		t.Fatalf("expected constraints with another whenUnsatisfiable to be kept, got %+v", *got)
+	}
+	if mergeTopologySpreadConstraintPtr(nil, nil) != nil {
+		t.Fatalf("expected nil for nil inputs")
+	}
+}
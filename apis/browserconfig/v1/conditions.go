@@ -0,0 +1,18 @@
+package v1
+
+// Condition types reported in ConfigStatus.Conditions.
+const (
+	// BrowserConfigValid indicates the merged spec can be rendered into browser pods.
+	BrowserConfigValid = "Valid"
+
+	// BrowserConfigConflicting indicates another BrowserConfig in the namespace defines the same browser version.
+	BrowserConfigConflicting = "Conflicting"
+)
+
+// Condition reasons set by the browserconfig-controller.
+const (
+	ReasonSpecValid       = "SpecValid"
+	ReasonSpecInvalid     = "SpecInvalid"
+	ReasonNoConflicts     = "NoConflicts"
+	ReasonVersionConflict = "VersionConflict"
+)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 	"time"
@@ -9,6 +10,7 @@ import (
 	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
 	"github.com/alcounit/browser-controller/controllers/browser"
 	"github.com/alcounit/browser-controller/controllers/browserconfig"
+	"github.com/alcounit/browser-controller/pkg/certs"
 	"github.com/alcounit/browser-controller/store"
 	"github.com/go-logr/logr"
 	"github.com/rs/zerolog"
@@ -17,7 +19,9 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
@@ -36,11 +40,57 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var poolSyncInterval time.Duration
+	var defaultConfigNamespace string
+	var enableWebhooks bool
+	var webhookPort int
+	var webhookCertDir string
+	var webhookSelfSigned bool
+	var webhookServiceName string
+	var webhookServiceNamespace string
+	var webhookConfigName string
+	var webhookCertSecret string
+	var watchNamespaces string
+	timeouts := browser.DefaultTimeouts()
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager.")
+	flag.DurationVar(&timeouts.PodCreation, "pod-creation-timeout", timeouts.PodCreation,
+		"How long a browser pod may stay Pending before the Browser is failed. Overridden by BrowserConfig startupTimeout.")
+	flag.DurationVar(&timeouts.PodDeletion, "pod-deletion-timeout", timeouts.PodDeletion,
+		"How long to wait for graceful browser pod deletion before forcing it. Overridden by BrowserConfig deletionTimeout.")
+	flag.DurationVar(&timeouts.PeriodicReconcile, "periodic-reconcile", timeouts.PeriodicReconcile,
+		"Requeue interval for running Browsers.")
+	flag.DurationVar(&timeouts.QuickCheck, "quick-check", timeouts.QuickCheck,
+		"Requeue interval while waiting for browser pod creation or deletion.")
+	flag.DurationVar(&timeouts.MediumRetry, "medium-retry", timeouts.MediumRetry,
+		"Requeue interval after a failed API call.")
+	flag.DurationVar(&poolSyncInterval, "pool-sync-interval", browser.DefaultPoolSyncInterval,
+		"How often warm pools of idle browser pods are refilled.")
+	flag.StringVar(&defaultConfigNamespace, "default-config-namespace", "",
+		"Namespace whose BrowserConfigs are cluster-wide defaults for namespaces without a matching entry. Disabled when empty.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma separated namespaces, or a label selector of namespaces, whose Browsers are managed. All namespaces when empty. "+
+			"A list of valid namespace names is always read as names, e.g. \"team\" is the namespace team.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the BrowserConfig and Browser admission webhooks.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"Directory holding tls.crt and tls.key of the webhook server.")
+	flag.BoolVar(&webhookSelfSigned, "webhook-self-signed", false,
+		"Generate a local CA and serving certificate in --webhook-cert-dir and inject the CA into the webhook configurations.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "browser-controller-webhook",
+		"Name of the Service in front of the webhook server, used for the self-signed certificate.")
+	flag.StringVar(&webhookServiceNamespace, "webhook-service-namespace", "default",
+		"Namespace of the Service in front of the webhook server, used for the self-signed certificate.")
+	flag.StringVar(&webhookCertSecret, "webhook-cert-secret", "browser-controller-webhook-certs",
+		"Secret in --webhook-service-namespace sharing the self-signed CA and certificate between replicas. "+
+			"Every replica generates its own when empty, only use that with a single replica.")
+	flag.StringVar(&webhookConfigName, "webhook-config-name", "browser-controller",
+		"Name of the validating and mutating webhook configurations the self-signed CA is injected into "+
+			"and, with --watch-namespaces, scoped to the watched namespaces.")
 	flag.Parse()
 
 	// zerolog setup
@@ -58,15 +108,72 @@ func main() {
 		os.Exit(1)
 	}
 
+	var namespaces []string
+	if watchNamespaces != "" {
+		c, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			log.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+		if namespaces, err = resolveWatchNamespaces(context.Background(), c, watchNamespaces); err != nil {
+			log.Error(err, "unable to resolve watched namespaces")
+			os.Exit(1)
+		}
+		log.Info("watching namespaces", "namespaces", namespaces)
+	}
+
+	if enableWebhooks && webhookSelfSigned {
+		c, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			log.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+
+		dnsNames := certs.ServiceDNSNames(webhookServiceName, webhookServiceNamespace)
+		var caBundle []byte
+		if webhookCertSecret != "" {
+			secret := client.ObjectKey{Namespace: webhookServiceNamespace, Name: webhookCertSecret}
+			caBundle, err = certs.EnsureSecret(context.Background(), c, secret, webhookCertDir, dnsNames)
+		} else {
+			caBundle, err = certs.EnsureSelfSigned(webhookCertDir, dnsNames)
+		}
+		if err != nil {
+			log.Error(err, "unable to create webhook certificates")
+			os.Exit(1)
+		}
+
+		if err := certs.InjectCABundle(context.Background(), c, webhookConfigName, caBundle); err != nil {
+			log.Error(err, "unable to inject webhook CA bundle")
+			os.Exit(1)
+		}
+	}
+
+	if enableWebhooks && len(namespaces) > 0 {
+		c, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			log.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+		if err := scopeWebhooks(context.Background(), c, webhookConfigName, namespaces, defaultConfigNamespace); err != nil {
+			log.Error(err, "unable to scope webhooks to watched namespaces")
+			os.Exit(1)
+		}
+	}
+
 	// Create manager
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme,
+		Cache:  cacheOptions(namespaces, defaultConfigNamespace),
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "controller.selenosis.io",
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
 	})
 	if err != nil {
 		log.Error(err, "unable to start manager")
@@ -74,26 +181,52 @@ func main() {
 	}
 
 	// Add BrowserConfig controller
-	browserCfg := browserconfig.NewBrowserConfigReconciler(mgr.GetClient(), mgr.GetScheme())
+	browserCfg := browserconfig.NewBrowserConfigReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("browserconfig-controller"))
 	if err = browserCfg.SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create browser config controller")
 		os.Exit(1)
 	}
 
+	// Add BrowserConfig validating webhook
+	if enableWebhooks {
+		if err := (&browserconfig.BrowserConfigValidator{}).SetupWebhookWithManager(mgr); err != nil {
+			log.Error(err, "unable to create browser config webhook")
+			os.Exit(1)
+		}
+	}
+
 	// Create BrowserConfigStore and register it as a manager runnable
-	browserCfgStore := store.NewBrowserConfigStore()
+	browserCfgStore := store.NewBrowserConfigStore().
+		WithDefaultNamespace(defaultConfigNamespace).
+		WithNamespaces(namespaces...)
 	if err := mgr.Add(browserCfgStore.WithCache(mgr.GetCache(), ctrl.Log)); err != nil {
 		log.Error(err, "unable to add browser config store to manager")
 		os.Exit(1)
 	}
 
 	// Add Browser controller
-	browserCtrl := browser.NewBrowserReconciler(mgr.GetClient(), browserCfgStore, mgr.GetScheme())
+	browserCtrl := browser.NewBrowserReconciler(mgr.GetClient(), browserCfgStore, mgr.GetScheme(), mgr.GetEventRecorderFor("browser-controller")).
+		WithTimeouts(timeouts).
+		WithAPIReader(mgr.GetAPIReader())
 	if err = browserCtrl.SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create browser controller")
 		os.Exit(1)
 	}
 
+	// Add Browser defaulting and validating webhook
+	if enableWebhooks {
+		if err := browser.NewBrowserWebhook(browserCfgStore).SetupWebhookWithManager(mgr); err != nil {
+			log.Error(err, "unable to create browser webhook")
+			os.Exit(1)
+		}
+	}
+
+	// Add warm pool manager
+	if err := mgr.Add(browser.NewPoolManager(mgr.GetClient(), browserCfgStore, poolSyncInterval)); err != nil {
+		log.Error(err, "unable to add browser pool manager to manager")
+		os.Exit(1)
+	}
+
 	// Setup health and readiness probes
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		log.Error(err, "unable to set up health check")
@@ -103,6 +236,17 @@ func main() {
 		log.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			log.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+		// Browsers are validated against the store, don't receive admission requests before it is filled
+		if err := mgr.AddReadyzCheck("browserconfig-store", browserCfgStore.ReadyCheck); err != nil {
+			log.Error(err, "unable to set up browser config store ready check")
+			os.Exit(1)
+		}
+	}
 
 	log.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
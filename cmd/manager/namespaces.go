@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// parseWatchNamespaces reads --watch-namespaces, either a comma separated list of namespace names
+// or a label selector of namespaces. Values that are not a list of valid names are parsed as a selector,
+// so a bare key such as "team" always names a namespace and never selects the namespaces labeled team.
+func parseWatchNamespaces(value string) ([]string, labels.Selector, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil, nil
+	}
+
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if len(validation.IsDNS1123Label(ns)) > 0 {
+			namespaces = nil
+			break
+		}
+		namespaces = append(namespaces, ns)
+	}
+	if len(namespaces) > 0 {
+		return namespaces, nil, nil
+	}
+
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("--watch-namespaces %q is neither a namespace list nor a label selector: %w", value, err)
+	}
+	return nil, selector, nil
+}
+
+// resolveWatchNamespaces returns the namespaces to watch, all namespaces when empty.
+// A label selector is resolved once at startup, namespaces labeled later require a restart.
+func resolveWatchNamespaces(ctx context.Context, c client.Reader, value string) ([]string, error) {
+	namespaces, selector, err := parseWatchNamespaces(value)
+	if err != nil || selector == nil {
+		return namespaces, err
+	}
+
+	list := &corev1.NamespaceList{}
+	if err := c.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("list namespaces matching %q: %w", selector.String(), err)
+	}
+	for _, ns := range list.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	if len(namespaces) == 0 {
+		return nil, fmt.Errorf("no namespace matches --watch-namespaces %q", selector.String())
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// cacheOptions restricts the manager cache to the watched namespaces. BrowserConfigs of the
+// default config namespace are cached too, so the cluster-wide defaults keep working.
+func cacheOptions(namespaces []string, defaultConfigNamespace string) cache.Options {
+	if len(namespaces) == 0 {
+		return cache.Options{}
+	}
+
+	opts := cache.Options{DefaultNamespaces: map[string]cache.Config{}}
+	for _, ns := range namespaces {
+		opts.DefaultNamespaces[ns] = cache.Config{}
+	}
+
+	if _, watched := opts.DefaultNamespaces[defaultConfigNamespace]; defaultConfigNamespace != "" && !watched {
+		configNamespaces := map[string]cache.Config{defaultConfigNamespace: {}}
+		for ns := range opts.DefaultNamespaces {
+			configNamespaces[ns] = cache.Config{}
+		}
+		opts.ByObject = map[client.Object]cache.ByObject{
+			&configv1.BrowserConfig{}: {Namespaces: configNamespaces},
+		}
+	}
+	return opts
+}
+
+// scopeWebhooks sets the namespaceSelector of every webhook of the named validating and mutating webhook
+// configurations to the watched namespaces, so a controller that is down only blocks admission in the
+// namespaces it manages. The BrowserConfig webhook covers the default config namespace too.
+// Configurations that do not exist are skipped.
+func scopeWebhooks(ctx context.Context, c client.Client, name string, namespaces []string, defaultConfigNamespace string) error {
+	configNamespaces := namespaces
+	if defaultConfigNamespace != "" && !slices.Contains(namespaces, defaultConfigNamespace) {
+		configNamespaces = append(slices.Clone(namespaces), defaultConfigNamespace)
+		sort.Strings(configNamespaces)
+	}
+
+	validating := &admissionv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, validating); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("get ValidatingWebhookConfiguration %s: %w", name, err)
+		}
+	} else {
+		patch := client.MergeFrom(validating.DeepCopy())
+		changed := false
+		for i := range validating.Webhooks {
+			selector := webhookNamespaceSelector(validating.Webhooks[i].Rules, namespaces, configNamespaces)
+			if !equality.Semantic.DeepEqual(validating.Webhooks[i].NamespaceSelector, selector) {
+				validating.Webhooks[i].NamespaceSelector = selector
+				changed = true
+			}
+		}
+		if changed {
+			if err := c.Patch(ctx, validating, patch); err != nil {
+				return fmt.Errorf("patch ValidatingWebhookConfiguration %s: %w", name, err)
+			}
+		}
+	}
+
+	mutating := &admissionv1.MutatingWebhookConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, mutating); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("get MutatingWebhookConfiguration %s: %w", name, err)
+		}
+		return nil
+	}
+	patch := client.MergeFrom(mutating.DeepCopy())
+	changed := false
+	for i := range mutating.Webhooks {
+		selector := webhookNamespaceSelector(mutating.Webhooks[i].Rules, namespaces, configNamespaces)
+		if !equality.Semantic.DeepEqual(mutating.Webhooks[i].NamespaceSelector, selector) {
+			mutating.Webhooks[i].NamespaceSelector = selector
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := c.Patch(ctx, mutating, patch); err != nil {
+		return fmt.Errorf("patch MutatingWebhookConfiguration %s: %w", name, err)
+	}
+	return nil
+}
+
+// webhookNamespaceSelector matches the namespaces a webhook with the rules admits objects of,
+// the config namespaces for BrowserConfig webhooks.
+func webhookNamespaceSelector(rules []admissionv1.RuleWithOperations, namespaces, configNamespaces []string) *metav1.LabelSelector {
+	for _, rule := range rules {
+		if slices.Contains(rule.Resources, "browserconfigs") {
+			namespaces = configNamespaces
+		}
+	}
+	return &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   namespaces,
+	}}}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseWatchNamespaces(t *testing.T) {
+	namespaces, selector, err := parseWatchNamespaces(" team-a, team-b ")
+	if err != nil || selector != nil || !reflect.DeepEqual(namespaces, []string{"team-a", "team-b"}) {
+		t.Fatalf("expected namespace list, got %v %v %v", namespaces, selector, err)
+	}
+
+	namespaces, selector, err = parseWatchNamespaces("selenosis.io/tenant in (a,b)")
+	if err != nil || namespaces != nil || selector == nil || selector.String() != "selenosis.io/tenant in (a,b)" {
+		t.Fatalf("expected label selector, got %v %v %v", namespaces, selector, err)
+	}
+
+	// a bare key is a namespace name, selecting by key needs an operator
+	namespaces, selector, err = parseWatchNamespaces("team")
+	if err != nil || selector != nil || !reflect.DeepEqual(namespaces, []string{"team"}) {
+		t.Fatalf("expected a bare key to be a namespace name, got %v %v %v", namespaces, selector, err)
+	}
+
+	if _, _, err := parseWatchNamespaces("team=a,="); err == nil {
+		t.Fatalf("expected invalid selector to fail")
+	}
+	if namespaces, selector, err := parseWatchNamespaces(""); namespaces != nil || selector != nil || err != nil {
+		t.Fatalf("expected all namespaces for an empty value")
+	}
+}
+
+func TestResolveWatchNamespaces(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "qa"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "qa"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build()
+
+	namespaces, err := resolveWatchNamespaces(context.Background(), c, "tenant=qa")
+	if err != nil || !reflect.DeepEqual(namespaces, []string{"team-a", "team-b"}) {
+		t.Fatalf("expected matching namespaces, got %v %v", namespaces, err)
+	}
+
+	if _, err := resolveWatchNamespaces(context.Background(), c, "tenant=none"); err == nil {
+		t.Fatalf("expected an error when no namespace matches")
+	}
+}
+
+func TestCacheOptions(t *testing.T) {
+	if opts := cacheOptions(nil, "browser-system"); opts.DefaultNamespaces != nil || opts.ByObject != nil {
+		t.Fatalf("expected a cluster-wide cache, got %+v", opts)
+	}
+
+	opts := cacheOptions([]string{"team-a"}, "team-a")
+	if _, ok := opts.DefaultNamespaces["team-a"]; !ok || len(opts.DefaultNamespaces) != 1 || opts.ByObject != nil {
+		t.Fatalf("unexpected cache options: %+v", opts)
+	}
+
+	opts = cacheOptions([]string{"team-a"}, "browser-system")
+	if len(opts.ByObject) != 1 {
+		t.Fatalf("expected BrowserConfig cache override, got %+v", opts.ByObject)
+	}
+	for obj, byObject := range opts.ByObject {
+		if _, ok := obj.(*configv1.BrowserConfig); !ok {
+			t.Fatalf("unexpected override for %T", obj)
+		}
+		if len(byObject.Namespaces) != 2 {
+			t.Fatalf("expected BrowserConfigs of watched and default namespaces, got %v", byObject.Namespaces)
+		}
+	}
+}
+
+func TestScopeWebhooks(t *testing.T) {
+	rules := func(resource string) []admissionv1.RuleWithOperations {
+		return []admissionv1.RuleWithOperations{{Rule: admissionv1.Rule{Resources: []string{resource}}}}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		&admissionv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a-browser-controller"},
+			Webhooks: []admissionv1.ValidatingWebhook{
+				{Name: "vbrowserconfig.selenosis.io", Rules: rules("browserconfigs")},
+				{Name: "vbrowser.selenosis.io", Rules: rules("browsers")},
+			},
+		},
+		&admissionv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a-browser-controller"},
+			Webhooks:   []admissionv1.MutatingWebhook{{Name: "mbrowser.selenosis.io", Rules: rules("browsers")}},
+		},
+	).Build()
+
+	if err := scopeWebhooks(context.Background(), c, "team-a-browser-controller", []string{"team-a"}, "browser-system"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	namespacesOf := func(selector *metav1.LabelSelector) []string {
+		if selector == nil || len(selector.MatchExpressions) != 1 || selector.MatchExpressions[0].Key != corev1.LabelMetadataName {
+			t.Fatalf("unexpected namespace selector: %+v", selector)
+		}
+		return selector.MatchExpressions[0].Values
+	}
+
+	validating := &admissionv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "team-a-browser-controller"}, validating); err != nil {
+		t.Fatalf("get validating webhook configuration: %v", err)
+	}
+	if got := namespacesOf(validating.Webhooks[0].NamespaceSelector); !reflect.DeepEqual(got, []string{"browser-system", "team-a"}) {
+		t.Fatalf("expected BrowserConfig webhook to cover the default config namespace, got %v", got)
+	}
+	if got := namespacesOf(validating.Webhooks[1].NamespaceSelector); !reflect.DeepEqual(got, []string{"team-a"}) {
+		t.Fatalf("expected Browser webhook scoped to the watched namespaces, got %v", got)
+	}
+
+	mutating := &admissionv1.MutatingWebhookConfiguration{}
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "team-a-browser-controller"}, mutating); err != nil {
+		t.Fatalf("get mutating webhook configuration: %v", err)
+	}
+	if got := namespacesOf(mutating.Webhooks[0].NamespaceSelector); !reflect.DeepEqual(got, []string{"team-a"}) {
+		t.Fatalf("expected Browser webhook scoped to the watched namespaces, got %v", got)
+	}
+
+	if err := scopeWebhooks(context.Background(), c, "missing", []string{"team-a"}, ""); err != nil {
+		t.Fatalf("expected missing configurations to be skipped, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Command render prints the browser pod the controller would create for a BrowserConfig,
+// a browser name/version and optional selenosis options, without a cluster.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/controllers/browser"
+	"github.com/alcounit/browser-controller/store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+func main() {
+	var configPath string
+	var browserName string
+	var browserVersion string
+	var options string
+	var name string
+	var namespace string
+
+	flag.StringVar(&configPath, "config", "", "BrowserConfig YAML file, may hold several documents. Use - for stdin.")
+	flag.StringVar(&browserName, "browser", "", "Browser name, as in Browser spec.browserName.")
+	flag.StringVar(&browserVersion, "version", "", "Browser version, as in Browser spec.browserVersion. Supports latest, prefixes and ranges.")
+	flag.StringVar(&options, "options", "", "Optional selenosis.io/options annotation JSON.")
+	flag.StringVar(&name, "name", "browser", "Name of the rendered Browser and pod.")
+	flag.StringVar(&namespace, "namespace", "", "Namespace of the rendered Browser. Defaults to the namespace of the first BrowserConfig.")
+	flag.Parse()
+
+	if configPath == "" || browserName == "" || browserVersion == "" {
+		fmt.Fprintln(os.Stderr, "--config, --browser and --version are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := render(os.Stdout, configPath, browserName, browserVersion, options, name, namespace); err != nil {
+		fmt.Fprintf(os.Stderr, "render: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func render(out io.Writer, configPath, browserName, browserVersion, options, name, namespace string) error {
+	configs, err := readConfigs(configPath)
+	if err != nil {
+		return err
+	}
+	if len(configs) == 0 {
+		return fmt.Errorf("no BrowserConfig found in %s", configPath)
+	}
+
+	if namespace == "" {
+		namespace = configs[0].Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	cfgStore := store.NewBrowserConfigStore()
+	for _, bc := range configs {
+		if bc.Namespace == "" {
+			bc.Namespace = namespace
+		}
+		cfgStore.Add(bc)
+	}
+
+	cfg, resolvedVersion, ok := cfgStore.Resolve(namespace, browserName, browserVersion)
+	if !ok {
+		return fmt.Errorf("no BrowserConfig entry found for %s:%s in namespace %s", browserName, browserVersion, namespace)
+	}
+
+	b := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				browserv1.SelenosisBrowserLabelKey:        name,
+				browserv1.SelenosisBrowserNameLabelKey:    browserName,
+				browserv1.SelenosisBrowserVersionLabelKey: resolvedVersion,
+			},
+		},
+		Spec: browserv1.BrowserSpec{
+			BrowserName:    browserName,
+			BrowserVersion: browserVersion,
+		},
+		Status: browserv1.BrowserStatus{ResolvedVersion: resolvedVersion},
+	}
+	if options != "" {
+		b.Annotations = map[string]string{browserv1.SelenosisOptionsAnnotationKey: options}
+	}
+
+	pod, err := browser.RenderPod(b, cfg)
+	if err != nil {
+		return err
+	}
+	pod.APIVersion = "v1"
+	pod.Kind = "Pod"
+
+	raw, err := yaml.Marshal(pod)
+	if err != nil {
+		return fmt.Errorf("marshal pod: %w", err)
+	}
+	_, err = out.Write(raw)
+	return err
+}
+
+// readConfigs decodes every BrowserConfig document of a YAML or JSON file.
+func readConfigs(path string) ([]*configv1.BrowserConfig, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var configs []*configv1.BrowserConfig
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		bc := &configv1.BrowserConfig{}
+		if err := decoder.Decode(bc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return configs, nil
+			}
+			return nil, fmt.Errorf("decode %s: %w", path, err)
+		}
+		if bc.Kind == "" {
+			continue
+		}
+		if bc.Kind != "BrowserConfig" {
+			return nil, fmt.Errorf("decode %s: unexpected kind %s", path, bc.Kind)
+		}
+		configs = append(configs, bc)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `apiVersion: selenosis.io/v1
+kind: BrowserConfig
+metadata:
+  name: cfg
+  namespace: qa
+spec:
+  template:
+    env:
+    - name: TZ
+      value: UTC
+    sidecars:
+    - name: seleniferous
+      image: seleniferous:1
+  browsers:
+    chrome:
+      "119.0":
+        image: chrome:119.0
+      "120.0":
+        image: chrome:120.0
+`
+
+func TestRender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := render(&out, path, "chrome", "latest", `{"labels":{"team":"qa"}}`, "b1", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"kind: Pod",
+		"namespace: qa",
+		"image: chrome:120.0",
+		"image: seleniferous:1",
+		"value: UTC",
+		"team: qa",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in rendered pod:\n%s", want, got)
+		}
+	}
+
+	if err := render(&out, path, "chrome", "121", "", "b1", ""); err == nil {
+		t.Fatalf("expected unknown version to fail")
+	}
+	if err := render(&out, path, "chrome", "120", `{"labels":`, "b1", ""); err == nil {
+		t.Fatalf("expected invalid options to fail")
+	}
+}
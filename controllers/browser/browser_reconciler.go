@@ -4,27 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
 	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
 	"github.com/alcounit/browser-controller/store"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logger "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
 	browserPodFinalizer = "browserpod.selenosis.io/finalizer"
 
-	maxRetries         = 5
+	maxRetries = 5
+
+	// Default lifecycle timeouts, see Timeouts
 	mediumRetry        = time.Second * 10
 	periodicReconcile  = time.Second * 30
 	quickCheck         = time.Second * 3
@@ -33,6 +42,33 @@ const (
 
 	browserContainerName = "browser"
 	sidecarContainerName = "seleniferous"
+
+	// Event reasons recorded on Browser resources
+	eventReasonPodCreated           = "PodCreated"
+	eventReasonPodClaimed           = "PodClaimed"
+	eventReasonPodCreateFailed      = "PodCreateFailed"
+	eventReasonPodRunning           = "PodRunning"
+	eventReasonPodFailed            = "PodFailed"
+	eventReasonPodDeleted           = "PodDeleted"
+	eventReasonPodDeleting          = "PodDeleting"
+	eventReasonPodForceDeleted      = "PodForceDeleted"
+	eventReasonConfigNotFound       = "ConfigNotFound"
+	eventReasonInvalidOptions       = "InvalidSelenosisOptions"
+	eventReasonCreationTimeout      = "CreationTimeout"
+	eventReasonContainerFailed      = "ContainerFailed"
+	eventReasonContainerTerminated  = "ContainerTerminated"
+	eventReasonMaxLifetimeExceeded  = "MaxLifetimeExceeded"
+	eventReasonTTLExpired           = "TTLAfterFinishedExpired"
+	eventReasonServiceCreated       = "ServiceCreated"
+	eventReasonServiceFailed        = "ServiceFailed"
+	eventReasonNetworkPolicyCreated = "NetworkPolicyCreated"
+	eventReasonNetworkPolicyDeleted = "NetworkPolicyDeleted"
+	eventReasonNetworkPolicyFailed  = "NetworkPolicyFailed"
+	eventReasonResourcesClamped     = "ResourcesClamped"
+	eventReasonResourcesRejected    = "ResourcesRejected"
+	eventReasonOptionsDenied        = "OptionsDenied"
+	eventReasonQueued               = "Queued"
+	eventReasonAdmitted             = "Admitted"
 )
 
 type SelenosisOptions struct {
@@ -42,25 +78,88 @@ type SelenosisOptions struct {
 
 type ContainerOption struct {
 	Env map[string]string `json:"env,omitempty"`
+	// Resources override requests and limits per resource, bounded by the BrowserConfig resourcePolicy
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// Timeouts holds the lifecycle durations used by BrowserReconciler.
+// PodCreation and PodDeletion can be overridden per browser version through
+// BrowserConfig startupTimeout and deletionTimeout.
+type Timeouts struct {
+	// PodCreation is how long a browser pod may stay Pending before the Browser is failed
+	PodCreation time.Duration
+	// PodDeletion is how long to wait for graceful pod deletion before forcing it
+	PodDeletion time.Duration
+	// PeriodicReconcile is the requeue interval for healthy Browsers
+	PeriodicReconcile time.Duration
+	// QuickCheck is the requeue interval while waiting for pod creation or deletion
+	QuickCheck time.Duration
+	// MediumRetry is the requeue interval after a failed API call
+	MediumRetry time.Duration
+}
+
+// DefaultTimeouts returns the built-in lifecycle timeouts.
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		PodCreation:       podCreationTimeout,
+		PodDeletion:       podDeletionTimeout,
+		PeriodicReconcile: periodicReconcile,
+		QuickCheck:        quickCheck,
+		MediumRetry:       mediumRetry,
+	}
 }
 
 // +kubebuilder:rbac:groups=selenosis.io,resources=browsers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=selenosis.io,resources=browsers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=selenosis.io,resources=browsers/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // BrowserReconciler reconciles Browser resources
 type BrowserReconciler struct {
-	client client.Client
-	config *store.BrowserConfigStore
-	scheme *runtime.Scheme
+	client   client.Client
+	config   *store.BrowserConfigStore
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	timeouts Timeouts
+	// apiReader reads uncached, see WithAPIReader
+	apiReader client.Reader
 }
 
-func NewBrowserReconciler(client client.Client, config *store.BrowserConfigStore, scheme *runtime.Scheme) *BrowserReconciler {
+func NewBrowserReconciler(client client.Client, config *store.BrowserConfigStore, scheme *runtime.Scheme, recorder record.EventRecorder) *BrowserReconciler {
 	return &BrowserReconciler{
-		client: client,
-		config: config,
-		scheme: scheme,
+		client:   client,
+		config:   config,
+		scheme:   scheme,
+		recorder: recorder,
+		timeouts: DefaultTimeouts(),
+	}
+}
+
+// WithAPIReader sets the uncached reader used to count the Browsers holding a quota slot. The cache may
+// not hold a Browser admitted by the previous reconcile yet, counting from it would admit past the quota.
+func (r *BrowserReconciler) WithAPIReader(reader client.Reader) *BrowserReconciler {
+	r.apiReader = reader
+	return r
+}
+
+// WithTimeouts overrides the default lifecycle timeouts, zero values keep the defaults.
+func (r *BrowserReconciler) WithTimeouts(t Timeouts) *BrowserReconciler {
+	defaults := DefaultTimeouts()
+	r.timeouts = Timeouts{
+		PodCreation:       durationOrDefault(t.PodCreation, defaults.PodCreation),
+		PodDeletion:       durationOrDefault(t.PodDeletion, defaults.PodDeletion),
+		PeriodicReconcile: durationOrDefault(t.PeriodicReconcile, defaults.PeriodicReconcile),
+		QuickCheck:        durationOrDefault(t.QuickCheck, defaults.QuickCheck),
+		MediumRetry:       durationOrDefault(t.MediumRetry, defaults.MediumRetry),
+	}
+	return r
+}
+
+func durationOrDefault(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
 	}
+	return d
 }
 
 // SetupWithManager sets up the controller with the Manager
@@ -68,6 +167,9 @@ func (r *BrowserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&browserv1.Browser{}).
 		Owns(&corev1.Pod{}).
+		Owns(&corev1.Service{}).
+		Owns(&networkingv1.NetworkPolicy{}, builder.MatchEveryOwner).
+		Watches(&browserv1.Browser{}, handler.EnqueueRequestsFromMapFunc(r.queuedBrowsers), builder.WithPredicates(queueChanged())).
 		Complete(r)
 }
 
@@ -82,12 +184,15 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err := r.client.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, browser); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Browser not found. Ignoring since must be deleted")
+			browserPhases.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "failed to get Browser")
 		return ctrl.Result{}, err
 	}
 
+	browserPhases.observe(browser)
+
 	// check if Browser deletion timestamp is set, if set handle deletion
 	if !browser.DeletionTimestamp.IsZero() {
 		log.Info("deleting Browser")
@@ -102,11 +207,10 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				controllerutil.RemoveFinalizer(b, browserPodFinalizer)
 			}); err != nil {
 				log.Error(err, "error removing Browser pod finalizer")
-				return ctrl.Result{RequeueAfter: mediumRetry}, err
+				return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 			}
 		}
-		log.Info("Browser is in Failed state, nothing to do")
-		return ctrl.Result{}, nil
+		return r.handleFinished(ctx, browser)
 	}
 
 	// ensure finalizer is set
@@ -120,18 +224,11 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		log.Info("adding finalizer to Browser")
 	}
 
-	// ensure label selenosis.io/browser.name exists
-	if browser.Labels["selenosis.io/browser"] != browser.Name {
-		if err := r.retryUpdate(ctx, browser, func(b *browserv1.Browser) {
-			if b.Labels == nil {
-				b.Labels = map[string]string{}
-			}
-			b.Labels["selenosis.io/browser"] = b.Name
-			b.Labels["selenosis.io/browser.name"] = b.Spec.BrowserName
-			b.Labels["selenosis.io/browser.version"] = b.Spec.BrowserVersion
-		}); err != nil {
+	// ensure selenosis.io/browser labels exist, the defaulting webhook sets them on admission when enabled
+	if needsBrowserLabels(browser) {
+		if err := r.retryUpdate(ctx, browser, setBrowserLabels); err != nil {
 			log.Error(err, "failed to update Browser with name label")
-			return ctrl.Result{RequeueAfter: mediumRetry}, err
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 		}
 		log.Info("label selenosis.io/browser.name assigned to Browser")
 	}
@@ -140,7 +237,7 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if browser.Status.Phase == "" {
 		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
 			b.Status.Phase = corev1.PodPending
-
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonPodPending, "waiting for browser pod")
 		}); err != nil {
 			log.Error(err, "failed to set initial Browser status")
 			return ctrl.Result{}, err
@@ -150,9 +247,16 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	log = log.WithValues("browserName", browser.Spec.BrowserName, "browserVersion", browser.Spec.BrowserVersion)
 
+	// enforce max lifetime
+	remaining, limited := r.remainingLifetime(browser)
+	if limited && remaining <= 0 {
+		log.Info("Browser max lifetime exceeded, deleting Browser")
+		return r.expireBrowser(ctx, browser)
+	}
+
 	//get the associated Pod
 	pod := &corev1.Pod{}
-	if err := r.client.Get(ctx, types.NamespacedName{Name: browser.GetName(), Namespace: browser.GetNamespace()}, pod); err != nil {
+	if err := r.client.Get(ctx, types.NamespacedName{Name: podName(browser), Namespace: browser.GetNamespace()}, pod); err != nil {
 		if errors.IsNotFound(err) {
 			if browser.Status.Phase == corev1.PodFailed {
 				log.Info("Browser is Failed state, browser pod not found. Ignoring since must be deleted")
@@ -175,29 +279,38 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	// Handle failed pod
 	if pod.Status.Phase == corev1.PodFailed {
+		alreadyFailed := browser.Status.Phase == corev1.PodFailed
 
 		if err := r.deletePod(ctx, pod); err != nil {
 			log.Info("deleting Browser Pod")
-			return ctrl.Result{RequeueAfter: mediumRetry}, err
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 		}
 
 		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
 			b.Status.Phase = corev1.PodFailed
 			b.Status.Message = fmt.Sprintf("pod has failed with reason: %s - %s", pod.Status.Reason, pod.Status.Message)
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonPodFailed, b.Status.Message)
 			log.Info("Browser Pod has failed", "reason", pod.Status.Reason, "message", pod.Status.Message)
 		}); err != nil {
 			log.Error(err, "failed to update Browser status to Failed")
-			return ctrl.Result{RequeueAfter: mediumRetry}, err
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+		}
+		r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonPodFailed,
+			"Browser pod failed: %s - %s", pod.Status.Reason, pod.Status.Message)
+		if !alreadyFailed {
+			recordSessionFailed(browser, browserv1.ReasonPodFailed)
 		}
 	}
 
 	if pod.Status.Phase == corev1.PodPending {
 
-		for _, cs := range pod.Status.ContainerStatuses {
-			if cs.State.Terminated != nil {
+		critical := criticalContainers(pod)
+		for _, cs := range runningContainerStatuses(pod) {
+			if _, ok := critical[cs.Name]; ok && cs.State.Terminated != nil {
 				if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
 					b.Status.Phase = corev1.PodFailed
 					b.Status.Message = fmt.Sprintf("pod container %s terminated", cs.Name)
+					setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonContainerTerminated, b.Status.Message)
 
 					log.Info("Browser Pod container terminated",
 						"container",
@@ -209,25 +322,33 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 						"exitCode",
 						cs.State.Terminated.ExitCode)
 				}); err != nil {
-					return ctrl.Result{RequeueAfter: mediumRetry}, err
+					return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 				}
+				r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonContainerTerminated,
+					"Container %s terminated before pod start: %s (exit code %d)", cs.Name, cs.State.Terminated.Reason, cs.State.Terminated.ExitCode)
+				recordSessionFailed(browser, browserv1.ReasonContainerTerminated)
 				return ctrl.Result{}, nil
 			}
 
 			if cs.State.Waiting != nil {
 				if !pod.CreationTimestamp.IsZero() {
+					startupTimeout := r.startupTimeout(browser)
 					podAge := time.Since(pod.CreationTimestamp.Time)
-					if podAge > podCreationTimeout {
+					if podAge > startupTimeout {
 						log.Info("Browser Pod creation timeout exceeded", "age", podAge.String(), "podStatus", pod.Status.Phase, "container", cs.Name)
 
 						if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
 							b.Status.Phase = corev1.PodFailed
 							b.Status.Message = fmt.Sprintf(
 								"pod creation timeout exceeded after %s",
-								podCreationTimeout.String())
+								startupTimeout.String())
+							setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonCreationTimeout, b.Status.Message)
 						}); err != nil {
-							return ctrl.Result{RequeueAfter: mediumRetry}, err
+							return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 						}
+						r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonCreationTimeout,
+							"Browser pod was not started within %s, container %s is waiting: %s", startupTimeout.String(), cs.Name, cs.State.Waiting.Reason)
+						recordSessionFailed(browser, browserv1.ReasonCreationTimeout)
 						return ctrl.Result{}, nil
 					}
 				}
@@ -237,7 +358,7 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 					log.Info("Browser Pod container not ready", "container", cs.Name, "reason", reason, "message", cs.State.Waiting.Message, "podStatus", pod.Status.Phase)
 
 					if err := r.deletePod(ctx, pod); err != nil {
-						return ctrl.Result{RequeueAfter: mediumRetry}, err
+						return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 					}
 
 					if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
@@ -245,16 +366,168 @@ func (r *BrowserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 						b.Status.Message = fmt.Sprintf(
 							"pod container %s failed: %s - %s",
 							cs.Name, reason, cs.State.Waiting.Message)
+						setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonContainerFailed, b.Status.Message)
+						if isImagePullFailure(reason) {
+							setCondition(b, browserv1.BrowserImagePulled, metav1.ConditionFalse, reason, cs.State.Waiting.Message)
+						}
 					}); err != nil {
-						return ctrl.Result{RequeueAfter: mediumRetry}, err
+						return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 					}
+					r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonContainerFailed,
+						"Container %s failed to start: %s - %s", cs.Name, reason, cs.State.Waiting.Message)
+					recordSessionFailed(browser, browserv1.ReasonContainerFailed)
 					return ctrl.Result{}, nil
 				}
 			}
 		}
 	}
 
-	return r.updateBrowserStatus(ctx, browser, pod)
+	// Expose the pod through a Service when the BrowserConfig asks for one and keep its
+	// NetworkPolicy in place, failures are reported but don't fail the session
+	if cfg, ok := r.browserConfig(browser); ok && cfg != nil && pod.DeletionTimestamp.IsZero() {
+		if cfg.Service != nil {
+			if err := r.ensureService(ctx, browser, pod, cfg.Service); err != nil {
+				log.Error(err, "failed to ensure Browser Service")
+				r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonServiceFailed, "Failed to ensure service: %v", err)
+			}
+		}
+		if err := r.syncNetworkPolicy(ctx, browser, pod, cfg.NetworkPolicy); err != nil {
+			log.Error(err, "failed to ensure Browser NetworkPolicy")
+			r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonNetworkPolicyFailed, "Failed to ensure network policy: %v", err)
+		}
+	}
+
+	result, err := r.updateBrowserStatus(ctx, browser, pod)
+	if err == nil && limited && result.RequeueAfter > remaining {
+		result.RequeueAfter = remaining
+	}
+	return result, err
+}
+
+// maxLifetime resolves the Browser max lifetime, preferring spec.maxLifetime over BrowserConfig maxLifetime.
+func (r *BrowserReconciler) maxLifetime(browser *browserv1.Browser) *metav1.Duration {
+	if browser.Spec.MaxLifetime != nil {
+		return browser.Spec.MaxLifetime
+	}
+	cfg, ok := r.browserConfig(browser)
+	if ok && cfg != nil {
+		return cfg.MaxLifetime
+	}
+	return nil
+}
+
+// remainingLifetime returns the time left before the Browser max lifetime is exceeded
+// and whether a max lifetime applies at all.
+func (r *BrowserReconciler) remainingLifetime(browser *browserv1.Browser) (time.Duration, bool) {
+	lifetime := r.maxLifetime(browser)
+	started := admittedAt(browser)
+	if lifetime == nil || lifetime.Duration <= 0 || started.IsZero() {
+		return 0, false
+	}
+	return lifetime.Duration - time.Since(started.Time), true
+}
+
+// admittedAt returns when the Browser was admitted by the namespace quota, the time spent queued
+// doesn't count against its lifetime. Queued Browsers return a zero time, Browsers created before
+// admission was recorded fall back to their creation time.
+func admittedAt(browser *browserv1.Browser) metav1.Time {
+	cond := meta.FindStatusCondition(browser.Status.Conditions, browserv1.BrowserAdmitted)
+	switch {
+	case cond == nil:
+		return browser.CreationTimestamp
+	case cond.Status == metav1.ConditionTrue:
+		return cond.LastTransitionTime
+	default:
+		return metav1.Time{}
+	}
+}
+
+// expireBrowser records why the Browser is being removed and deletes it,
+// pod cleanup continues through the finalizer in handleDeletion.
+func (r *BrowserReconciler) expireBrowser(ctx context.Context, browser *browserv1.Browser) (ctrl.Result, error) {
+	log := logger.FromContext(ctx)
+
+	message := fmt.Sprintf("Browser exceeded max lifetime of %s", r.maxLifetime(browser).Duration.String())
+	if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+		b.Status.Reason = browserv1.ReasonMaxLifetimeExceeded
+		b.Status.Message = message
+		setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonMaxLifetimeExceeded, message)
+		setCondition(b, browserv1.BrowserTerminating, metav1.ConditionTrue, browserv1.ReasonMaxLifetimeExceeded, message)
+	}); err != nil {
+		log.Error(err, "failed to update Browser status")
+		return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+	}
+
+	r.recorder.Event(browser, corev1.EventTypeNormal, eventReasonMaxLifetimeExceeded, message)
+
+	if err := r.client.Delete(ctx, browser); err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "failed to delete expired Browser")
+		return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// ttlAfterFinished resolves the Failed Browser retention, preferring spec.ttlSecondsAfterFinished
+// over BrowserConfig ttlSecondsAfterFinished.
+func (r *BrowserReconciler) ttlAfterFinished(browser *browserv1.Browser) *int32 {
+	if browser.Spec.TTLSecondsAfterFinished != nil {
+		return browser.Spec.TTLSecondsAfterFinished
+	}
+	cfg, ok := r.browserConfig(browser)
+	if ok && cfg != nil {
+		return cfg.TTLSecondsAfterFinished
+	}
+	return nil
+}
+
+// handleFinished stamps the completion time of a Failed Browser and deletes it once its TTL expires
+func (r *BrowserReconciler) handleFinished(ctx context.Context, browser *browserv1.Browser) (ctrl.Result, error) {
+	log := logger.FromContext(ctx)
+
+	completionTime := browser.Status.CompletionTime
+	if completionTime == nil {
+		now := metav1.Now()
+		completionTime = &now
+		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+			b.Status.CompletionTime = &now
+		}); err != nil {
+			log.Error(err, "failed to set Browser completion time")
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+		}
+	}
+
+	ttl := r.ttlAfterFinished(browser)
+	if ttl == nil {
+		log.Info("Browser is in Failed state, nothing to do")
+		return ctrl.Result{}, nil
+	}
+
+	ttlDuration := time.Duration(*ttl) * time.Second
+	if remaining := ttlDuration - time.Since(completionTime.Time); remaining > 0 {
+		log.Info("Browser is in Failed state, waiting for TTL to expire", "remaining", remaining.String())
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	// the Ready condition keeps the reason the Browser failed
+	message := fmt.Sprintf("Deleting Browser %s after finishing", ttlDuration.String())
+	if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+		b.Status.Reason = browserv1.ReasonTTLExpired
+		b.Status.Message = message
+		setCondition(b, browserv1.BrowserTerminating, metav1.ConditionTrue, browserv1.ReasonTTLExpired, message)
+	}); err != nil {
+		log.Error(err, "failed to update Browser status")
+		return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+	}
+
+	r.recorder.Event(browser, corev1.EventTypeNormal, eventReasonTTLExpired, message)
+
+	if err := r.client.Delete(ctx, browser); err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "failed to delete finished Browser")
+		return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+	}
+	recordSessionDeleted(browser)
+	log.Info("finished Browser deleted after TTL")
+	return ctrl.Result{}, nil
 }
 
 // handleDeletion processes Browser resource deletion
@@ -266,9 +539,19 @@ func (r *BrowserReconciler) handleDeletion(ctx context.Context, browser *browser
 		return ctrl.Result{}, nil
 	}
 
+	if !meta.IsStatusConditionTrue(browser.Status.Conditions, browserv1.BrowserTerminating) {
+		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+			setCondition(b, browserv1.BrowserTerminating, metav1.ConditionTrue, browserv1.ReasonBrowserDeleted, "Browser deletion requested")
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonBrowserDeleted, "Browser deletion requested")
+		}); err != nil {
+			// Don't block Browser deletion on a status update
+			log.Error(err, "failed to set Browser Terminating condition")
+		}
+	}
+
 	// Get the pod
 	pod := &corev1.Pod{}
-	err := r.client.Get(ctx, types.NamespacedName{Name: browser.GetName(), Namespace: browser.GetNamespace()}, pod)
+	err := r.client.Get(ctx, types.NamespacedName{Name: podName(browser), Namespace: browser.GetNamespace()}, pod)
 
 	// Delete pod if exists
 	if err == nil {
@@ -283,28 +566,32 @@ func (r *BrowserReconciler) handleDeletion(ctx context.Context, browser *browser
 
 			if err := r.client.Delete(ctx, pod, deleteOptions...); err != nil && !errors.IsNotFound(err) {
 				log.Error(err, "failed to delete Browser pod")
-				return ctrl.Result{RequeueAfter: mediumRetry}, err
+				return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 			}
+			r.recorder.Event(browser, corev1.EventTypeNormal, eventReasonPodDeleting, "Deleting browser pod")
 		}
 
 		// Check if pod deletion is taking too long
 		if pod.DeletionTimestamp != nil {
+			deletionTimeout := r.deletionTimeout(browser)
 			deletionTime := pod.DeletionTimestamp.Time
-			if time.Since(deletionTime) > podDeletionTimeout {
+			if time.Since(deletionTime) > deletionTimeout {
 				log.Info("Pod deletion is taking too long, attempting force delete")
 				if err := r.deletePod(ctx, pod); err != nil {
 					log.Error(err, "Failed to force delete pod after timeout")
 					// Continue anyway to remove finalizer
 				}
+				r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonPodForceDeleted,
+					"Browser pod was not deleted within %s, forcing deletion", deletionTimeout.String())
 			} else {
 				// Wait for pod to be deleted
 				log.Info("waiting for pod to be deleted")
-				return ctrl.Result{RequeueAfter: quickCheck}, nil
+				return ctrl.Result{RequeueAfter: r.timeouts.QuickCheck}, nil
 			}
 		} else {
 			// Wait for pod to be deleted
 			log.Info("waiting for pod to be deleted")
-			return ctrl.Result{RequeueAfter: quickCheck}, nil
+			return ctrl.Result{RequeueAfter: r.timeouts.QuickCheck}, nil
 		}
 	} else if !errors.IsNotFound(err) {
 		log.Error(err, "error checking Browser pod for deletion")
@@ -318,10 +605,11 @@ func (r *BrowserReconciler) handleDeletion(ctx context.Context, browser *browser
 			controllerutil.RemoveFinalizer(b, browserPodFinalizer)
 		}); err != nil {
 			log.Error(err, "error removing Browser pod finalizer")
-			return ctrl.Result{RequeueAfter: mediumRetry}, err
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 		}
 	}
 
+	recordSessionDeleted(browser)
 	log.Info("Browser cleanup completed")
 	return ctrl.Result{}, nil
 }
@@ -344,6 +632,24 @@ func (r *BrowserReconciler) deletePod(ctx context.Context, pod *corev1.Pod) erro
 func (r *BrowserReconciler) handleMissingPod(ctx context.Context, browser *browserv1.Browser) (ctrl.Result, error) {
 	log := logger.FromContext(ctx)
 
+	// A pod deleted from under the Browser is recreated, record that its session was lost
+	if podObserved(browser) {
+		message := fmt.Sprintf("Browser pod %s was deleted, recreating it", podName(browser))
+		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+			b.Status.Phase = corev1.PodPending
+			b.Status.PodName = ""
+			b.Status.PodIP = ""
+			b.Status.StartTime = nil
+			b.Status.ContainerStatuses = nil
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonPodDeleted, message)
+		}); err != nil {
+			log.Error(err, "failed to record deleted Browser Pod")
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+		}
+		r.recorder.Event(browser, corev1.EventTypeWarning, eventReasonPodDeleted, message)
+		log.Info("Browser Pod was deleted, recreating it")
+	}
+
 	key := fmt.Sprintf("%s/%s:%s",
 		browser.Namespace,
 		browser.Spec.BrowserName,
@@ -352,20 +658,25 @@ func (r *BrowserReconciler) handleMissingPod(ctx context.Context, browser *brows
 
 	log.Info("looking up browser config", "key", key)
 
-	browserSpec, exists := r.config.Get(browser.GetNamespace(), browser.Spec.BrowserName, browser.Spec.BrowserVersion)
+	browserSpec, resolvedVersion, exists := r.config.Resolve(browser.GetNamespace(), browser.Spec.BrowserName, browser.Spec.BrowserVersion)
 	if !exists || browserSpec == nil {
 		if browser.Status.Phase != corev1.PodFailed {
 			if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
 				b.Status.Phase = corev1.PodFailed
 				b.Status.Reason = "BrowserPodSpec"
 				b.Status.Message = "Browser configuration not found"
+				setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonConfigNotFound,
+					fmt.Sprintf("no BrowserConfig entry for %s", key))
 			}); err != nil {
 				log.Error(err, "Failed to update Browser status")
 				return ctrl.Result{}, err
 			}
 			log.Info("Browser status set to Failed")
+			recordSessionFailed(browser, browserv1.ReasonConfigNotFound)
 		}
 
+		r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonConfigNotFound,
+			"No BrowserConfig entry found for %s:%s", browser.Spec.BrowserName, browser.Spec.BrowserVersion)
 		log.Info("Browser config not found", "key", key, "browserName", browser.Spec.BrowserName, "BrowserVersion", browser.Spec.BrowserVersion)
 		return ctrl.Result{}, nil
 	}
@@ -377,29 +688,167 @@ func (r *BrowserReconciler) handleMissingPod(ctx context.Context, browser *brows
 			b.Status.Phase = corev1.PodFailed
 			b.Status.Reason = "InvalidSelenosisOptions"
 			b.Status.Message = err.Error()
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonInvalidOptions, err.Error())
 		}); err != nil {
 			log.Error(err, "Failed to update Browser status")
 			return ctrl.Result{}, err
 		}
 
+		r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonInvalidOptions,
+			"Invalid %s annotation: %v", browserv1.SelenosisOptionsAnnotationKey, err)
+		recordSessionFailed(browser, browserv1.ReasonInvalidOptions)
 		log.Info("Invalid selenosis options")
 		return ctrl.Result{}, nil
 	}
 
 	log.Info("parsed selenosis options", "hasOptions", opts != nil)
 
+	if err := optionsDenied(optionsViolations(opts, browserSpec.OptionsPolicy)); err != nil {
+		log.Info("Selenosis options denied by policy", "reason", err.Error())
+		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+			b.Status.Phase = corev1.PodFailed
+			b.Status.Reason = browserv1.ReasonOptionsDenied
+			b.Status.Message = err.Error()
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonOptionsDenied, err.Error())
+		}); err != nil {
+			log.Error(err, "Failed to update Browser status")
+			return ctrl.Result{}, err
+		}
+
+		r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonOptionsDenied, "Selenosis options denied: %v", err)
+		recordSessionFailed(browser, browserv1.ReasonOptionsDenied)
+		return ctrl.Result{}, nil
+	}
+
+	adjusted, err := boundResources(opts, browserSpec.ResourcePolicy)
+	if err != nil {
+		log.Info("Selenosis resource overrides rejected", "reason", err.Error())
+		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+			b.Status.Phase = corev1.PodFailed
+			b.Status.Reason = browserv1.ReasonResourcesRejected
+			b.Status.Message = err.Error()
+			setCondition(b, browserv1.BrowserResources, metav1.ConditionFalse, browserv1.ReasonResourcesRejected, err.Error())
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonResourcesRejected, err.Error())
+		}); err != nil {
+			log.Error(err, "Failed to update Browser status")
+			return ctrl.Result{}, err
+		}
+
+		r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonResourcesRejected, "Resource overrides rejected: %v", err)
+		recordSessionFailed(browser, browserv1.ReasonResourcesRejected)
+		return ctrl.Result{}, nil
+	}
+
+	// Pin the concrete version so later lookups don't drift when "latest" moves
+	if browser.Status.ResolvedVersion != resolvedVersion {
+		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+			b.Status.ResolvedVersion = resolvedVersion
+		}); err != nil {
+			log.Error(err, "failed to record resolved browser version")
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+		}
+		browser.Status.ResolvedVersion = resolvedVersion
+		log.Info("browser version resolved", "resolvedVersion", resolvedVersion)
+	}
+
+	// Queue the Browser while the namespace quota is exhausted
+	if admitted, result, err := r.admit(ctx, browser); !admitted {
+		return result, err
+	}
+
+	if hasResourceOverrides(opts) {
+		reason, message := browserv1.ReasonResourcesApplied, "resource overrides applied as requested"
+		if adjusted != "" {
+			reason, message = browserv1.ReasonResourcesClamped, adjusted
+		}
+		if cond := meta.FindStatusCondition(browser.Status.Conditions, browserv1.BrowserResources); cond == nil || cond.Reason != reason || cond.Message != message {
+			if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+				setCondition(b, browserv1.BrowserResources, metav1.ConditionTrue, reason, message)
+			}); err != nil {
+				log.Error(err, "failed to record resource overrides")
+				return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+			}
+			setCondition(browser, browserv1.BrowserResources, metav1.ConditionTrue, reason, message)
+			if adjusted != "" {
+				r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonResourcesClamped, "Resource overrides clamped: %s", adjusted)
+			}
+		}
+	}
+
+	// Restrict the network access before the pod starts
+	if err := r.syncNetworkPolicy(ctx, browser, nil, browserSpec.NetworkPolicy); err != nil {
+		log.Error(err, "failed to ensure Browser NetworkPolicy")
+		r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonNetworkPolicyFailed, "Failed to ensure network policy: %v", err)
+		return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, nil
+	}
+
+	// Claim a pre-provisioned pod from the warm pool if one is ready
+	if browserSpec.Pool != nil && canClaimPooledPod(opts) {
+		pod, err := r.claimPooledPod(ctx, browser, opts, browserSpec.OptionsPolicy)
+		if err != nil {
+			log.Error(err, "failed to claim pooled Browser Pod, creating a new one")
+		}
+		recordPoolClaim(browser, pod != nil)
+
+		if pod != nil {
+			if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+				b.Status.PodName = pod.Name
+			}); err != nil {
+				log.Error(err, "failed to record claimed Browser Pod")
+				return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+			}
+
+			r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonPodClaimed, "Claimed pooled browser pod %s", pod.Name)
+			recordSessionCreated(browser)
+			log.Info("Browser Pod claimed from pool", "pod", pod.Name)
+			return ctrl.Result{RequeueAfter: r.timeouts.QuickCheck}, nil
+		}
+	}
+
 	// Create pod from template
 	if err := r.createPod(ctx, browser, browserSpec, opts); err != nil {
 		if errors.IsAlreadyExists(err) {
 			log.Info("Browser Pod already exists, will reconcile on next iteration")
-			return ctrl.Result{RequeueAfter: quickCheck}, nil
+			return ctrl.Result{RequeueAfter: r.timeouts.QuickCheck}, nil
 		}
 		log.Error(err, "failed to create Browser Pod")
+		r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonPodCreateFailed, "Failed to create browser pod: %v", err)
 		return ctrl.Result{}, err
 	}
 
+	r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonPodCreated, "Created browser pod with image %s", browserSpec.Image)
+	recordSessionCreated(browser)
 	log.Info("Browser Pod created")
-	return ctrl.Result{RequeueAfter: quickCheck}, nil
+	return ctrl.Result{RequeueAfter: r.timeouts.QuickCheck}, nil
+}
+
+// browserConfig looks up the Browser config, pinned to the version resolved when its pod was created
+func (r *BrowserReconciler) browserConfig(browser *browserv1.Browser) (*configv1.BrowserVersionConfigSpec, bool) {
+	version := browser.Spec.BrowserVersion
+	if browser.Status.ResolvedVersion != "" {
+		version = browser.Status.ResolvedVersion
+	}
+	return r.config.Get(browser.GetNamespace(), browser.Spec.BrowserName, version)
+}
+
+// startupTimeout resolves the pod creation timeout for the Browser,
+// preferring the BrowserConfig startupTimeout over the controller default.
+func (r *BrowserReconciler) startupTimeout(browser *browserv1.Browser) time.Duration {
+	cfg, ok := r.browserConfig(browser)
+	if ok && cfg != nil && cfg.StartupTimeout != nil && cfg.StartupTimeout.Duration > 0 {
+		return cfg.StartupTimeout.Duration
+	}
+	return r.timeouts.PodCreation
+}
+
+// deletionTimeout resolves the pod deletion timeout for the Browser,
+// preferring the BrowserConfig deletionTimeout over the controller default.
+func (r *BrowserReconciler) deletionTimeout(browser *browserv1.Browser) time.Duration {
+	cfg, ok := r.browserConfig(browser)
+	if ok && cfg != nil && cfg.DeletionTimeout != nil && cfg.DeletionTimeout.Duration > 0 {
+		return cfg.DeletionTimeout.Duration
+	}
+	return r.timeouts.PodDeletion
 }
 
 // createPod creates a Pod for Browser with optimized memory usage
@@ -417,21 +866,28 @@ func (r *BrowserReconciler) updateBrowserStatus(ctx context.Context, browser *br
 	log := logger.FromContext(ctx)
 
 	// Check for critical container termination
-	for _, containerStatus := range pod.Status.ContainerStatuses {
+	critical := criticalContainers(pod)
+	for _, containerStatus := range runningContainerStatuses(pod) {
 		// Check if it's a critical container and if it has terminated state
-		if (containerStatus.Name == browserContainerName || containerStatus.Name == sidecarContainerName) &&
-			containerStatus.State.Terminated != nil {
+		if _, ok := critical[containerStatus.Name]; ok && containerStatus.State.Terminated != nil {
 
 			if browser.Status.Phase != corev1.PodFailed {
 				if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
 					b.Status.Phase = corev1.PodFailed
 					b.Status.Reason = pod.Status.Reason
 					b.Status.Message = pod.Status.Message
+
+					message := fmt.Sprintf("critical container %s terminated", containerStatus.Name)
+					setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonContainerTerminated, message)
+					setCondition(b, browserv1.BrowserTerminating, metav1.ConditionTrue, browserv1.ReasonContainerTerminated, message)
 				}); err != nil {
 					log.Error(err, "Failed to update Browser status")
 					return ctrl.Result{}, err
 				}
 				log.Info("Browser status set to Failed")
+				r.recorder.Eventf(browser, corev1.EventTypeWarning, eventReasonContainerTerminated,
+					"Critical container %s terminated, deleting Browser", containerStatus.Name)
+				recordSessionFailed(browser, browserv1.ReasonContainerTerminated)
 			}
 
 			log.Info("Browser Pod container statuses",
@@ -453,12 +909,14 @@ func (r *BrowserReconciler) updateBrowserStatus(ctx context.Context, browser *br
 		(pod.Status.StartTime != nil && (browser.Status.StartTime == nil || !browser.Status.StartTime.Equal(pod.Status.StartTime)))
 
 	containersStatusChanged := false
+	conditionsChanged := applyPodConditions(browser.DeepCopy(), pod)
 
-	newContainerStatuses := make([]browserv1.ContainerStatus, 0, len(pod.Status.ContainerStatuses))
+	statuses := runningContainerStatuses(pod)
+	newContainerStatuses := make([]browserv1.ContainerStatus, 0, len(statuses))
 
 	// Efficiently collect container statuses
-	if len(pod.Status.ContainerStatuses) > 0 {
-		for _, containerStatus := range pod.Status.ContainerStatuses {
+	if len(statuses) > 0 {
+		for _, containerStatus := range statuses {
 			status := browserv1.ContainerStatus{
 				Name:         containerStatus.Name,
 				State:        containerStatus.State,
@@ -486,7 +944,7 @@ func (r *BrowserReconciler) updateBrowserStatus(ctx context.Context, browser *br
 	}
 
 	// Update status if changed
-	if browserStatusChanged || containersStatusChanged {
+	if browserStatusChanged || containersStatusChanged || conditionsChanged {
 		if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
 			if browserStatusChanged {
 				b.Status.PodIP = pod.Status.PodIP
@@ -498,14 +956,116 @@ func (r *BrowserReconciler) updateBrowserStatus(ctx context.Context, browser *br
 			if containersStatusChanged {
 				b.Status.ContainerStatuses = newContainerStatuses
 			}
+
+			applyPodConditions(b, pod)
 		}); err != nil {
 			log.Error(err, "Failed to update Browser status")
 			return ctrl.Result{}, err
 		}
+
+		if browser.Status.Phase != corev1.PodRunning && pod.Status.Phase == corev1.PodRunning {
+			r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonPodRunning, "Browser pod is running on %s", pod.Spec.NodeName)
+			recordStartupDuration(browser, time.Now())
+		}
 	}
 
 	log.Info("reconcilation completed")
-	return ctrl.Result{RequeueAfter: periodicReconcile}, nil
+	return ctrl.Result{RequeueAfter: r.timeouts.PeriodicReconcile}, nil
+}
+
+// setCondition records a Browser condition stamped with the current generation
+// and reports whether the condition list changed.
+func setCondition(b *browserv1.Browser, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	b.Status.ObservedGeneration = b.Generation
+	return meta.SetStatusCondition(&b.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: b.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// applyPodConditions derives Scheduled, ImagePulled and Ready conditions from the Pod state
+// and reports whether any of them changed.
+func applyPodConditions(b *browserv1.Browser, pod *corev1.Pod) bool {
+	changed := b.Status.ObservedGeneration != b.Generation
+
+	if scheduled := getPodCondition(pod, corev1.PodScheduled); scheduled != nil {
+		if scheduled.Status == corev1.ConditionTrue {
+			changed = setCondition(b, browserv1.BrowserScheduled, metav1.ConditionTrue, browserv1.ReasonPodScheduled, "") || changed
+		} else {
+			changed = setCondition(b, browserv1.BrowserScheduled, metav1.ConditionFalse,
+				reasonOrDefault(scheduled.Reason, browserv1.ReasonPodNotScheduled), scheduled.Message) || changed
+		}
+	}
+
+	status, reason, message := imagePullState(pod)
+	changed = setCondition(b, browserv1.BrowserImagePulled, status, reason, message) || changed
+
+	ready := getPodCondition(pod, corev1.PodReady)
+	if ready != nil && ready.Status == corev1.ConditionTrue {
+		changed = setCondition(b, browserv1.BrowserReady, metav1.ConditionTrue, browserv1.ReasonPodReady, "") || changed
+	} else {
+		reason, message := browserv1.ReasonPodNotReady, ""
+		if ready != nil {
+			reason = reasonOrDefault(ready.Reason, reason)
+			message = ready.Message
+		}
+		changed = setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, reason, message) || changed
+	}
+
+	return changed
+}
+
+// imagePullState reports whether the images of all Pod containers have been pulled.
+func imagePullState(pod *corev1.Pod) (metav1.ConditionStatus, string, string) {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, cs := range statuses {
+		if cs.State.Waiting != nil && isImagePullFailure(cs.State.Waiting.Reason) {
+			return metav1.ConditionFalse, cs.State.Waiting.Reason,
+				fmt.Sprintf("container %s: %s", cs.Name, cs.State.Waiting.Message)
+		}
+	}
+
+	if len(statuses) == 0 {
+		return metav1.ConditionUnknown, browserv1.ReasonImagesPulling, ""
+	}
+
+	for _, cs := range statuses {
+		if cs.ImageID == "" && cs.State.Running == nil && cs.State.Terminated == nil {
+			return metav1.ConditionUnknown, browserv1.ReasonImagesPulling, fmt.Sprintf("waiting for container %s image", cs.Name)
+		}
+	}
+
+	return metav1.ConditionTrue, browserv1.ReasonImagesPulled, ""
+}
+
+func isImagePullFailure(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+		return true
+	}
+	return false
+}
+
+func getPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func reasonOrDefault(reason, fallback string) string {
+	if reason == "" {
+		return fallback
+	}
+	return reason
 }
 
 func containerStateEqual(a, b corev1.ContainerState) bool {
@@ -537,13 +1097,88 @@ func containerStateEqual(a, b corev1.ContainerState) bool {
 	return true
 }
 
-// getContainerPorts returns ports for a container with optimized memory usage
-func getContainerPorts(containerName string, pod *corev1.Pod) []browserv1.ContainerPort {
-	if pod.Spec.Containers == nil {
-		return []browserv1.ContainerPort{}
+// criticalContainerNames lists the containers whose termination ends the session: the browser
+// container followed by the sidecars and native sidecars marked critical.
+func criticalContainerNames(cfg *configv1.BrowserVersionConfigSpec) []string {
+	names := []string{browserContainerName}
+	if cfg.Sidecars != nil {
+		for _, s := range *cfg.Sidecars {
+			if isCriticalSidecar(s) {
+				names = append(names, s.Name)
+			}
+		}
 	}
+	if cfg.InitContainers != nil {
+		for _, s := range *cfg.InitContainers {
+			if s.IsNativeSidecar() && isCriticalSidecar(s) {
+				names = append(names, s.Name)
+			}
+		}
+	}
+	return names
+}
 
-	for _, container := range pod.Spec.Containers {
+// isCriticalSidecar defaults critical to true for the seleniferous sidecar only.
+func isCriticalSidecar(s configv1.Sidecar) bool {
+	if s.Critical != nil {
+		return *s.Critical
+	}
+	return s.Name == sidecarContainerName
+}
+
+// criticalContainers returns the critical containers recorded on the pod. Pods created without
+// the annotation treat the browser and seleniferous containers as critical.
+func criticalContainers(pod *corev1.Pod) map[string]struct{} {
+	value, ok := pod.Annotations[browserv1.SelenosisCriticalContainersAnnotationKey]
+	if !ok {
+		return map[string]struct{}{browserContainerName: {}, sidecarContainerName: {}}
+	}
+
+	critical := map[string]struct{}{}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			critical[name] = struct{}{}
+		}
+	}
+	return critical
+}
+
+// runningContainers returns the pod containers followed by its native sidecars,
+// the init containers with restartPolicy Always that run next to the browser.
+func runningContainers(pod *corev1.Pod) []corev1.Container {
+	containers := append([]corev1.Container{}, pod.Spec.Containers...)
+	for _, c := range pod.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			containers = append(containers, c)
+		}
+	}
+	return containers
+}
+
+// runningContainerStatuses returns the statuses of the containers returned by runningContainers.
+// Statuses of init containers that run to completion are skipped, their termination is expected.
+func runningContainerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.ContainerStatuses...)
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if isNativeSidecar(pod, cs.Name) {
+			statuses = append(statuses, cs)
+		}
+	}
+	return statuses
+}
+
+func isNativeSidecar(pod *corev1.Pod, name string) bool {
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == name {
+			return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+		}
+	}
+	return false
+}
+
+// getContainerPorts returns ports for a container with optimized memory usage
+func getContainerPorts(containerName string, pod *corev1.Pod) []browserv1.ContainerPort {
+	for _, container := range runningContainers(pod) {
 		if container.Name == containerName && len(container.Ports) > 0 {
 			ports := make([]browserv1.ContainerPort, 0, len(container.Ports))
 			for _, port := range container.Ports {
@@ -631,7 +1266,7 @@ func (r *BrowserReconciler) deleteBrowser(ctx context.Context, browser *browserv
 			controllerutil.RemoveFinalizer(b, browserPodFinalizer)
 		}); err != nil {
 			log.Error(err, "error removing Browser pod finalizer")
-			return ctrl.Result{RequeueAfter: mediumRetry}, err
+			return ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
 		}
 	}
 
@@ -640,9 +1275,32 @@ func (r *BrowserReconciler) deleteBrowser(ctx context.Context, browser *browserv
 		log.Error(err, "failed to delete Browser with terminated container")
 		return ctrl.Result{}, err
 	}
+	recordSessionDeleted(browser)
 	return ctrl.Result{}, nil
 }
 
+// podObserved reports whether the Browser status was derived from its pod, the status is only
+// updated once the pod is in the cache, so a pod missing afterwards was deleted.
+func podObserved(browser *browserv1.Browser) bool {
+	return browser.Status.StartTime != nil || browser.Status.PodIP != "" || len(browser.Status.ContainerStatuses) > 0
+}
+
+// RenderPod returns the pod the reconciler creates for the Browser from a merged
+// browser version config, including the selenosis options of the Browser annotations.
+func RenderPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionConfigSpec) (*corev1.Pod, error) {
+	opts, err := parseSelenosisOptions(browser.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	if err := optionsDenied(optionsViolations(opts, cfg.OptionsPolicy)); err != nil {
+		return nil, err
+	}
+	if _, err := boundResources(opts, cfg.ResourcePolicy); err != nil {
+		return nil, err
+	}
+	return buildBrowserPod(browser, cfg, opts), nil
+}
+
 func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionConfigSpec, opts *SelenosisOptions) *corev1.Pod {
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -673,6 +1331,10 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 				initContainer.Env = append(initContainer.Env, *ic.Env...)
 			}
 
+			if ic.EnvFrom != nil {
+				initContainer.EnvFrom = append(initContainer.EnvFrom, *ic.EnvFrom...)
+			}
+
 			if ic.Resources != nil {
 				initContainer.Resources = *ic.Resources
 			}
@@ -681,6 +1343,21 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 				initContainer.Command = *ic.Command
 			}
 
+			if ic.Args != nil {
+				initContainer.Args = *ic.Args
+			}
+
+			initContainer.SecurityContext = ic.SecurityContext
+
+			// native sidecars keep running next to the browser and may be probed
+			if ic.IsNativeSidecar() {
+				initContainer.RestartPolicy = ic.RestartPolicy
+				initContainer.ReadinessProbe = ic.ReadinessProbe
+				initContainer.LivenessProbe = ic.LivenessProbe
+				initContainer.StartupProbe = ic.StartupProbe
+				initContainer.Lifecycle = ic.Lifecycle
+			}
+
 			if ic.Ports != nil {
 				initContainer.Ports = *ic.Ports
 			}
@@ -709,9 +1386,25 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 	if cfg.Env != nil {
 		browserContainer.Env = *cfg.Env
 	}
+	if cfg.EnvFrom != nil {
+		browserContainer.EnvFrom = *cfg.EnvFrom
+	}
 	if cfg.Resources != nil {
 		browserContainer.Resources = *cfg.Resources
 	}
+	if cfg.Args != nil {
+		browserContainer.Args = *cfg.Args
+	}
+
+	browserContainer.ReadinessProbe = cfg.ReadinessProbe
+	browserContainer.LivenessProbe = cfg.LivenessProbe
+	browserContainer.StartupProbe = cfg.StartupProbe
+	browserContainer.Lifecycle = cfg.Lifecycle
+
+	// copied, Privileged is set on it below
+	if cfg.ContainerSecurityContext != nil {
+		browserContainer.SecurityContext = cfg.ContainerSecurityContext.DeepCopy()
+	}
 
 	if cfg.VolumeMounts != nil {
 		browserContainer.VolumeMounts = append(browserContainer.VolumeMounts, *cfg.VolumeMounts...)
@@ -753,6 +1446,10 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 				sidecar.Env = append(sidecar.Env, *s.Env...)
 			}
 
+			if s.EnvFrom != nil {
+				sidecar.EnvFrom = append(sidecar.EnvFrom, *s.EnvFrom...)
+			}
+
 			if s.Resources != nil {
 				sidecar.Resources = *s.Resources
 			}
@@ -761,6 +1458,16 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 				sidecar.Command = *s.Command
 			}
 
+			if s.Args != nil {
+				sidecar.Args = *s.Args
+			}
+
+			sidecar.ReadinessProbe = s.ReadinessProbe
+			sidecar.LivenessProbe = s.LivenessProbe
+			sidecar.StartupProbe = s.StartupProbe
+			sidecar.Lifecycle = s.Lifecycle
+			sidecar.SecurityContext = s.SecurityContext
+
 			if s.Ports != nil {
 				sidecar.Ports = *s.Ports
 			}
@@ -793,6 +1500,10 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 		for k, v := range browser.Labels {
 			pod.Labels[k] = v
 		}
+		// the Browser label may still hold the version query, the pod runs the resolved version
+		if _, ok := browser.Labels[browserv1.SelenosisBrowserVersionLabelKey]; ok {
+			pod.Labels[browserv1.SelenosisBrowserVersionLabelKey] = versionLabelValue(browser)
+		}
 	}
 
 	// Pod-level fields
@@ -805,6 +1516,15 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 		}
 	}
 
+	if npLabels := networkPolicyPodLabels(browser, cfg); npLabels != nil {
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		for k, v := range npLabels {
+			pod.Labels[k] = v
+		}
+	}
+
 	if browser.Annotations != nil {
 		if pod.Annotations == nil {
 			pod.Annotations = map[string]string{}
@@ -826,6 +1546,12 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 		}
 	}
 
+	// recorded on the pod so config changes don't affect running sessions
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[browserv1.SelenosisCriticalContainersAnnotationKey] = strings.Join(criticalContainerNames(cfg), ",")
+
 	if cfg.NodeSelector != nil {
 		pod.Spec.NodeSelector = *cfg.NodeSelector
 	}
@@ -850,10 +1576,28 @@ func buildBrowserPod(browser *browserv1.Browser, cfg *configv1.BrowserVersionCon
 		pod.Spec.DNSConfig = cfg.DNSConfig
 	}
 
+	if cfg.TopologySpreadConstraints != nil {
+		pod.Spec.TopologySpreadConstraints = *cfg.TopologySpreadConstraints
+	}
+
+	if cfg.PriorityClassName != nil {
+		pod.Spec.PriorityClassName = *cfg.PriorityClassName
+	}
+
+	pod.Spec.RuntimeClassName = cfg.RuntimeClassName
+
+	if cfg.ServiceAccountName != nil {
+		pod.Spec.ServiceAccountName = *cfg.ServiceAccountName
+	}
+
+	pod.Spec.TerminationGracePeriodSeconds = cfg.TerminationGracePeriodSeconds
+	pod.Spec.ShareProcessNamespace = cfg.ShareProcessNamespace
+	pod.Spec.EnableServiceLinks = cfg.EnableServiceLinks
+
 	pod.Spec.Hostname = browser.GetName()
 	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
 
-	applySelenosisOptions(pod, opts)
+	applySelenosisOptions(pod, opts, cfg.OptionsPolicy)
 
 	return pod
 }
@@ -881,19 +1625,22 @@ func parseSelenosisOptions(ann map[string]string) (*SelenosisOptions, error) {
 	return &opts, nil
 }
 
-func applySelenosisOptions(pod *corev1.Pod, opts *SelenosisOptions) {
+// applySelenosisOptions applies the selenosis options to the pod, skipping the overrides the
+// options policy does not allow. The reconciler fails Browsers with such overrides before.
+func applySelenosisOptions(pod *corev1.Pod, opts *SelenosisOptions, policy *configv1.OptionsPolicy) {
 	if pod == nil || opts == nil {
 		return
 	}
 
 	if len(opts.Containers) > 0 {
 		for i := range pod.Spec.Containers {
-			name := pod.Spec.Containers[i].Name
-			option, ok := opts.Containers[name]
-			if !ok || len(option.Env) == 0 {
-				continue
+			applyContainerOptions(&pod.Spec.Containers[i], opts, policy)
+		}
+		// native sidecars run next to the browser and take the same overrides
+		for i, c := range pod.Spec.InitContainers {
+			if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+				applyContainerOptions(&pod.Spec.InitContainers[i], opts, policy)
 			}
-			pod.Spec.Containers[i].Env = mergeEnvVars(pod.Spec.Containers[i].Env, option.Env)
 		}
 	}
 
@@ -902,11 +1649,33 @@ func applySelenosisOptions(pod *corev1.Pod, opts *SelenosisOptions) {
 			pod.Labels = map[string]string{}
 		}
 		for k, v := range opts.Labels {
-			pod.Labels[k] = v
+			if labelAllowed(k, policy) {
+				pod.Labels[k] = v
+			}
 		}
 	}
 }
 
+// applyContainerOptions merges the env and resource overrides of the container the options policy allows.
+func applyContainerOptions(c *corev1.Container, opts *SelenosisOptions, policy *configv1.OptionsPolicy) {
+	option, ok := opts.Containers[c.Name]
+	if !ok || !containerAllowed(c.Name, policy) {
+		return
+	}
+	env := make(map[string]string, len(option.Env))
+	for k, v := range option.Env {
+		if envAllowed(k, policy) {
+			env[k] = v
+		}
+	}
+	if len(env) > 0 {
+		c.Env = mergeEnvVars(c.Env, env)
+	}
+	if option.Resources != nil {
+		c.Resources = mergeResources(c.Resources, *option.Resources)
+	}
+}
+
 func mergeEnvVars(base []corev1.EnvVar, override map[string]string) []corev1.EnvVar {
 	if len(override) == 0 {
 		return base
@@ -918,8 +1687,15 @@ func mergeEnvVars(base []corev1.EnvVar, override map[string]string) []corev1.Env
 		idx[out[i].Name] = i
 	}
 
-	for k, v := range override {
-		ev := corev1.EnvVar{Name: k, Value: v}
+	// new variables are appended in name order so rendered pods are stable
+	keys := make([]string, 0, len(override))
+	for k := range override {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ev := corev1.EnvVar{Name: k, Value: override[k]}
 		if pos, ok := idx[k]; ok {
 			out[pos] = ev
 		} else {
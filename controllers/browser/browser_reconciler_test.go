@@ -13,10 +13,15 @@ import (
 	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
 	"github.com/alcounit/browser-controller/store"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -29,6 +34,9 @@ func newBrowserScheme(t *testing.T) *runtime.Scheme {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		t.Fatalf("add corev1 scheme: %v", err)
 	}
+	if err := networkingv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add networkingv1 scheme: %v", err)
+	}
 	if err := browserv1.AddToScheme(scheme); err != nil {
 		t.Fatalf("add browserv1 scheme: %v", err)
 	}
@@ -270,7 +278,7 @@ func TestApplySelenosisOptionsMergesEnvAndLabels(t *testing.T) {
 		},
 	}
 
-	applySelenosisOptions(pod, opts)
+	applySelenosisOptions(pod, opts, nil)
 
 	if pod.Labels["existing"] != "1" || pod.Labels["from"] != "options" {
 		t.Fatalf("expected labels to be merged, got %+v", pod.Labels)
@@ -288,11 +296,44 @@ func TestApplySelenosisOptionsMergesEnvAndLabels(t *testing.T) {
 	}
 }
 
+func TestApplySelenosisOptionsToNativeSidecars(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "browser"}},
+			InitContainers: []corev1.Container{
+				{Name: "init"},
+				{Name: "seleniferous", RestartPolicy: &always},
+			},
+		},
+	}
+	memory := resource.MustParse("256Mi")
+	opts := &SelenosisOptions{
+		Containers: map[string]ContainerOption{
+			"init":         {Env: map[string]string{"A": "1"}},
+			"seleniferous": {Env: map[string]string{"A": "1"}, Resources: &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: memory}}},
+		},
+	}
+
+	applySelenosisOptions(pod, opts, nil)
+
+	if len(pod.Spec.InitContainers[0].Env) != 0 {
+		t.Fatalf("expected init container running to completion to be left untouched, got %+v", pod.Spec.InitContainers[0].Env)
+	}
+	sidecar := pod.Spec.InitContainers[1]
+	if val, ok := envValue(sidecar.Env, "A"); !ok || val != "1" {
+		t.Fatalf("expected env override on the native sidecar, got %+v", sidecar.Env)
+	}
+	if got := sidecar.Resources.Limits[corev1.ResourceMemory]; got.Cmp(memory) != 0 {
+		t.Fatalf("expected resource override on the native sidecar, got %+v", sidecar.Resources)
+	}
+}
+
 func TestHandleMissingPodConfigNotFound(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	cfgStore := store.NewBrowserConfigStore()
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, cfgStore, scheme)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(100))
 
 	brw := &browserv1.Browser{
 		ObjectMeta: metav1.ObjectMeta{
@@ -340,7 +381,7 @@ func TestHandleMissingPodStatusUpdateError(t *testing.T) {
 		Status: browserv1.BrowserStatus{Phase: corev1.PodPending},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, cfgStore, scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, cfgStore, scheme, record.NewFakeRecorder(100))
 
 	_, err := r.handleMissingPod(context.Background(), brw)
 	if err == nil {
@@ -355,7 +396,7 @@ func TestHandleMissingPodCreatesPod(t *testing.T) {
 	setStoreConfig(t, cfgStore, "ns/chrome:120", spec)
 
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, cfgStore, scheme)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(100))
 
 	brw := &browserv1.Browser{
 		ObjectMeta: metav1.ObjectMeta{
@@ -385,6 +426,46 @@ func TestHandleMissingPodCreatesPod(t *testing.T) {
 	}
 }
 
+func TestHandleMissingPodRecordsResolvedVersion(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120.0", &configv1.BrowserVersionConfigSpec{Image: "img-120.0"})
+	setStoreConfig(t, cfgStore, "ns/chrome:121.0", &configv1.BrowserVersionConfigSpec{Image: "img-121.0"})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "latest"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(100))
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	updated := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, updated); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if updated.Status.ResolvedVersion != "121.0" {
+		t.Fatalf("expected resolved version 121.0, got %q", updated.Status.ResolvedVersion)
+	}
+
+	pod := &corev1.Pod{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, pod); err != nil {
+		t.Fatalf("expected pod to be created: %v", err)
+	}
+	if pod.Spec.Containers[0].Image != "img-121.0" {
+		t.Fatalf("expected latest image, got %q", pod.Spec.Containers[0].Image)
+	}
+
+	// lookups stay pinned to the resolved version once a newer one appears
+	setStoreConfig(t, cfgStore, "ns/chrome:122.0", &configv1.BrowserVersionConfigSpec{Image: "img-122.0"})
+	if cfg, ok := r.browserConfig(updated); !ok || cfg.Image != "img-121.0" {
+		t.Fatalf("expected config pinned to resolved version")
+	}
+}
+
 func TestHandleMissingPodInvalidSelenosisOptions(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	cfgStore := store.NewBrowserConfigStore()
@@ -392,7 +473,7 @@ func TestHandleMissingPodInvalidSelenosisOptions(t *testing.T) {
 	setStoreConfig(t, cfgStore, "ns/chrome:120", spec)
 
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, cfgStore, scheme)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(100))
 
 	brw := &browserv1.Browser{
 		ObjectMeta: metav1.ObjectMeta{
@@ -443,7 +524,7 @@ func TestHandleMissingPodInvalidSelenosisOptions(t *testing.T) {
 func TestUpdateBrowserStatusCriticalContainer(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	now := metav1.NewTime(time.Now().UTC())
 	brw := &browserv1.Browser{
@@ -494,7 +575,7 @@ func TestUpdateBrowserStatusCriticalContainer(t *testing.T) {
 func TestUpdateBrowserStatusUpdatesFields(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	now := metav1.NewTime(time.Now().UTC())
 	brw := &browserv1.Browser{
@@ -550,7 +631,7 @@ func TestUpdateBrowserStatusUpdatesFields(t *testing.T) {
 func TestReconcileNotFound(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "missing"},
@@ -563,7 +644,7 @@ func TestReconcileNotFound(t *testing.T) {
 func TestReconcileAddsFinalizerAndLabels(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	brw := &browserv1.Browser{
 		ObjectMeta: metav1.ObjectMeta{
@@ -611,7 +692,7 @@ func TestReconcileFailedBrowserRemovesFinalizer(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -642,7 +723,7 @@ func TestReconcileFailedBrowserFinalizerRemoveError(t *testing.T) {
 		},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -664,7 +745,7 @@ func TestHandleDeletionNoFinalizer(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	now := metav1.NewTime(time.Now().UTC())
 	brw.DeletionTimestamp = &now
@@ -690,7 +771,7 @@ func TestHandleDeletionPodNotFound(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.handleDeletion(context.Background(), brw)
 	if err != nil {
@@ -718,7 +799,7 @@ func TestHandleDeletionPodDeletionInProgress(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.handleDeletion(context.Background(), brw)
 	if err != nil {
@@ -750,7 +831,7 @@ func TestHandleDeletionPodTimeout(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.handleDeletion(context.Background(), brw)
 	if err != nil {
@@ -781,7 +862,7 @@ func TestHandleMissingPodAlreadyExists(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, cfgStore, scheme)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(100))
 
 	res, err := r.handleMissingPod(context.Background(), brw)
 	if err != nil {
@@ -816,7 +897,7 @@ func TestReconcilePodFailedUpdatesStatus(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -866,7 +947,7 @@ func TestReconcilePodPendingContainerTerminated(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -910,7 +991,7 @@ func TestReconcilePodPendingWaitingBadReason(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -953,7 +1034,7 @@ func TestReconcilePodPendingCreationTimeout(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -981,7 +1062,7 @@ func TestUpdateBrowserStatusNoChanges(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1159,6 +1240,267 @@ func TestBuildBrowserPodAllFields(t *testing.T) {
 	}
 }
 
+func TestBuildBrowserPodContainerSpec(t *testing.T) {
+	args := []string{"--headless"}
+	probe := &corev1.Probe{ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(5900)}}}
+	preStop := &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 5}}}
+	envFrom := []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}}}}
+	runAsUser := int64(1000)
+	containerSecCtx := &corev1.SecurityContext{RunAsUser: &runAsUser}
+	sidecarArgs := []string{":99"}
+	sidecars := []configv1.Sidecar{{
+		Name:            "vnc",
+		Image:           "vnc",
+		Args:            &sidecarArgs,
+		EnvFrom:         &envFrom,
+		ReadinessProbe:  probe,
+		LivenessProbe:   probe,
+		StartupProbe:    probe,
+		Lifecycle:       preStop,
+		SecurityContext: containerSecCtx,
+	}}
+	initContainers := []configv1.Sidecar{{Name: "init", Image: "init", Args: &sidecarArgs, EnvFrom: &envFrom, SecurityContext: containerSecCtx}}
+	priv := true
+
+	cfg := &configv1.BrowserVersionConfigSpec{
+		Image:                    "browser",
+		Args:                     &args,
+		EnvFrom:                  &envFrom,
+		ReadinessProbe:           probe,
+		LivenessProbe:            probe,
+		StartupProbe:             probe,
+		Lifecycle:                preStop,
+		ContainerSecurityContext: containerSecCtx,
+		Privileged:               &priv,
+		Sidecars:                 &sidecars,
+		InitContainers:           &initContainers,
+	}
+	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
+
+	pod := buildBrowserPod(brw, cfg, nil)
+
+	main := pod.Spec.Containers[0]
+	if len(main.Args) != 1 || len(main.EnvFrom) != 1 || main.ReadinessProbe != probe || main.LivenessProbe != probe ||
+		main.StartupProbe != probe || main.Lifecycle != preStop {
+		t.Fatalf("expected container spec on main container, got %+v", main)
+	}
+	if main.SecurityContext == nil || *main.SecurityContext.RunAsUser != 1000 || !*main.SecurityContext.Privileged {
+		t.Fatalf("expected containerSecurityContext combined with privileged, got %+v", main.SecurityContext)
+	}
+	if containerSecCtx.Privileged != nil {
+		t.Fatalf("expected config security context not to be modified")
+	}
+
+	sidecar := pod.Spec.Containers[1]
+	if len(sidecar.Args) != 1 || len(sidecar.EnvFrom) != 1 || sidecar.ReadinessProbe != probe || sidecar.LivenessProbe != probe ||
+		sidecar.StartupProbe != probe || sidecar.Lifecycle != preStop || sidecar.SecurityContext != containerSecCtx {
+		t.Fatalf("expected container spec on sidecar, got %+v", sidecar)
+	}
+
+	init := pod.Spec.InitContainers[0]
+	if len(init.Args) != 1 || len(init.EnvFrom) != 1 || init.SecurityContext != containerSecCtx {
+		t.Fatalf("expected container spec on init container, got %+v", init)
+	}
+}
+
+func TestBuildBrowserPodNativeSidecar(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	probe := &corev1.Probe{}
+	preStop := &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 5}}}
+	initContainers := []configv1.Sidecar{
+		{Name: "setup", Image: "setup"},
+		{Name: "xvfb", Image: "xvfb", RestartPolicy: &always, StartupProbe: probe, Lifecycle: preStop},
+	}
+	cfg := &configv1.BrowserVersionConfigSpec{Image: "browser", InitContainers: &initContainers}
+	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
+
+	pod := buildBrowserPod(brw, cfg, nil)
+	if len(pod.Spec.InitContainers) != 2 {
+		t.Fatalf("expected init containers, got %+v", pod.Spec.InitContainers)
+	}
+	if setup := pod.Spec.InitContainers[0]; setup.RestartPolicy != nil {
+		t.Fatalf("expected classic init container, got %+v", setup)
+	}
+	xvfb := pod.Spec.InitContainers[1]
+	if xvfb.RestartPolicy == nil || *xvfb.RestartPolicy != corev1.ContainerRestartPolicyAlways {
+		t.Fatalf("expected native sidecar restart policy, got %+v", xvfb.RestartPolicy)
+	}
+	if xvfb.StartupProbe != probe || xvfb.Lifecycle != preStop {
+		t.Fatalf("expected probes and lifecycle on native sidecar")
+	}
+}
+
+func TestUpdateBrowserStatusNativeSidecars(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cl := newBrowserClient(scheme)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, recorder)
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns", Finalizers: []string{browserPodFinalizer}},
+	}
+	if err := cl.Create(context.Background(), brw); err != nil {
+		t.Fatalf("create browser: %v", err)
+	}
+
+	always := corev1.ContainerRestartPolicyAlways
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "setup"},
+				{Name: sidecarContainerName, RestartPolicy: &always, Ports: []corev1.ContainerPort{{ContainerPort: 4445}}},
+			},
+			Containers: []corev1.Container{{Name: browserContainerName}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "setup", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+				{Name: sidecarContainerName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: browserContainerName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+
+	if _, err := r.updateBrowserStatus(context.Background(), brw, pod); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal PodRunning")
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	statuses := got.Status.ContainerStatuses
+	if len(statuses) != 2 || statuses[1].Name != sidecarContainerName || len(statuses[1].Ports) != 1 {
+		t.Fatalf("expected browser and native sidecar statuses without completed init container, got %+v", statuses)
+	}
+
+	pod.Status.InitContainerStatuses[1].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+	if _, err := r.updateBrowserStatus(context.Background(), got, pod); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Warning ContainerTerminated")
+
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected terminated native seleniferous sidecar to delete the Browser, got %v", err)
+	}
+}
+
+func TestBuildBrowserPodCriticalContainers(t *testing.T) {
+	critical, notCritical := true, false
+	always := corev1.ContainerRestartPolicyAlways
+	sidecars := []configv1.Sidecar{
+		{Name: "seleniferous", Image: "s", Critical: &notCritical},
+		{Name: "proxy", Image: "p", Critical: &critical},
+		{Name: "video", Image: "v"},
+	}
+	initContainers := []configv1.Sidecar{
+		{Name: "setup", Image: "setup"},
+		{Name: "xvfb", Image: "xvfb", RestartPolicy: &always, Critical: &critical},
+	}
+	cfg := &configv1.BrowserVersionConfigSpec{Image: "browser", Sidecars: &sidecars, InitContainers: &initContainers}
+	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
+
+	pod := buildBrowserPod(brw, cfg, nil)
+	if got := pod.Annotations[browserv1.SelenosisCriticalContainersAnnotationKey]; got != "browser,proxy,xvfb" {
+		t.Fatalf("unexpected critical containers %q", got)
+	}
+
+	cfg = &configv1.BrowserVersionConfigSpec{Image: "browser", Sidecars: &[]configv1.Sidecar{{Name: "seleniferous", Image: "s"}}}
+	pod = buildBrowserPod(brw, cfg, nil)
+	if got := pod.Annotations[browserv1.SelenosisCriticalContainersAnnotationKey]; got != "browser,seleniferous" {
+		t.Fatalf("expected seleniferous to be critical by default, got %q", got)
+	}
+}
+
+func TestUpdateBrowserStatusNonCriticalContainerTerminated(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cl := newBrowserClient(scheme)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, recorder)
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns", Finalizers: []string{browserPodFinalizer}},
+		Status:     browserv1.BrowserStatus{Phase: corev1.PodRunning},
+	}
+	if err := cl.Create(context.Background(), brw); err != nil {
+		t.Fatalf("create browser: %v", err)
+	}
+
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "b1",
+			Namespace:   "ns",
+			Annotations: map[string]string{browserv1.SelenosisCriticalContainersAnnotationKey: "browser,proxy"},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: browserContainerName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: sidecarContainerName, State: terminated},
+				{Name: "proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+
+	res, err := r.updateBrowserStatus(context.Background(), brw, pod)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.RequeueAfter != periodicReconcile {
+		t.Fatalf("expected non-critical termination to keep the session, got %+v", res)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), &browserv1.Browser{}); err != nil {
+		t.Fatalf("expected Browser to be kept: %v", err)
+	}
+
+	pod.Status.ContainerStatuses[2].State = terminated
+	if _, err := r.updateBrowserStatus(context.Background(), brw, pod); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Warning ContainerTerminated")
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), &browserv1.Browser{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected critical proxy termination to delete the Browser, got %v", err)
+	}
+}
+
+func TestBuildBrowserPodSchedulingFields(t *testing.T) {
+	priorityClass, runtimeClass, serviceAccount := "ci-preemptible", "gvisor", "browsers"
+	grace := int64(10)
+	share, links := true, false
+	spread := []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}}
+	cfg := &configv1.BrowserVersionConfigSpec{
+		Image:                         "browser",
+		TopologySpreadConstraints:     &spread,
+		PriorityClassName:             &priorityClass,
+		RuntimeClassName:              &runtimeClass,
+		ServiceAccountName:            &serviceAccount,
+		TerminationGracePeriodSeconds: &grace,
+		ShareProcessNamespace:         &share,
+		EnableServiceLinks:            &links,
+	}
+	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
+
+	spec := buildBrowserPod(brw, cfg, nil).Spec
+	if len(spec.TopologySpreadConstraints) != 1 || spec.PriorityClassName != priorityClass ||
+		spec.RuntimeClassName == nil || *spec.RuntimeClassName != runtimeClass || spec.ServiceAccountName != serviceAccount {
+		t.Fatalf("expected scheduling fields on pod, got %+v", spec)
+	}
+	if *spec.TerminationGracePeriodSeconds != 10 || !*spec.ShareProcessNamespace || *spec.EnableServiceLinks {
+		t.Fatalf("expected pod fields on pod, got %+v", spec)
+	}
+
+	spec = buildBrowserPod(brw, &configv1.BrowserVersionConfigSpec{Image: "browser"}, nil).Spec
+	if spec.RuntimeClassName != nil || spec.TerminationGracePeriodSeconds != nil || spec.EnableServiceLinks != nil {
+		t.Fatalf("expected unset fields to keep API defaults, got %+v", spec)
+	}
+}
+
 func TestBuildBrowserPodBrowserLabelsOnly(t *testing.T) {
 	cfg := &configv1.BrowserVersionConfigSpec{
 		Image: "browser",
@@ -1200,7 +1542,7 @@ func (e errorClient) Delete(ctx context.Context, obj client.Object, opts ...clie
 func TestDeletePodNotFound(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	cl := newBrowserClient(scheme)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.deletePod(context.Background(), &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "ns"},
@@ -1215,7 +1557,7 @@ func TestDeletePodError(t *testing.T) {
 	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
 	base := newBrowserClient(scheme, pod)
 	cl := errorClient{Client: base, deleteErr: apierrors.NewInternalError(errors.New("delete"))}
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.deletePod(context.Background(), pod)
 	if err == nil {
@@ -1245,7 +1587,7 @@ func TestHandleMissingPodCreateError(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw)
 	cl := errorClient{Client: base, createErr: apierrors.NewInternalError(errors.New("boom"))}
-	r := NewBrowserReconciler(cl, cfgStore, scheme)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(100))
 
 	_, err := r.handleMissingPod(context.Background(), brw)
 	if err == nil {
@@ -1276,7 +1618,7 @@ func TestReconcilePodDeletedDeletesBrowser(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1297,7 +1639,7 @@ func TestReconcilePodNotFoundBrowserFailed(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1307,6 +1649,64 @@ func TestReconcilePodNotFoundBrowserFailed(t *testing.T) {
 	}
 }
 
+func TestReconcileDeletedPodRecreatesPod(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "img"})
+	started := metav1.NewTime(time.Now().Add(-time.Minute))
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "b1",
+			Namespace:  "ns",
+			Finalizers: []string{browserPodFinalizer},
+			Labels: map[string]string{
+				"selenosis.io/browser":         "b1",
+				"selenosis.io/browser.name":    "chrome",
+				"selenosis.io/browser.version": "120",
+			},
+		},
+		Spec:   browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+		Status: browserv1.BrowserStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1", StartTime: &started},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, corev1.EventTypeWarning+" "+eventReasonPodDeleted)
+	expectEvent(t, recorder, corev1.EventTypeNormal+" "+eventReasonPodCreated)
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.Phase != corev1.PodPending || got.Status.PodIP != "" || got.Status.StartTime != nil {
+		t.Fatalf("expected status of the deleted pod to be cleared, got %+v", got.Status)
+	}
+	if cond := meta.FindStatusCondition(got.Status.Conditions, browserv1.BrowserReady); cond == nil || cond.Reason != browserv1.ReasonPodDeleted {
+		t.Fatalf("expected Ready condition with reason %s, got %+v", browserv1.ReasonPodDeleted, cond)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected the deleted pod to be recreated, got %v", err)
+	}
+
+	// the recreated pod is not reported as deleted again while it is not in the cache
+	if _, err := r.handleMissingPod(context.Background(), got); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	select {
+	case event := <-recorder.Events:
+		if strings.HasPrefix(event, corev1.EventTypeWarning+" "+eventReasonPodDeleted) {
+			t.Fatalf("expected PodDeleted to be recorded once, got %q", event)
+		}
+	default:
+	}
+}
+
 func TestReconcilePodPendingContainerCreatingNoTimeout(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	brw := &browserv1.Browser{
@@ -1342,7 +1742,7 @@ func TestReconcilePodPendingContainerCreatingNoTimeout(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1398,7 +1798,7 @@ func (s *statusPatchErrorWriter) Patch(ctx context.Context, obj client.Object, p
 func TestRetryUpdateGetError(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	base := newBrowserClient(scheme)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, getErr: apierrors.NewBadRequest("bad")}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, getErr: apierrors.NewBadRequest("bad")}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryUpdate(context.Background(), &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}, func(*browserv1.Browser) {})
 	if err == nil {
@@ -1410,7 +1810,7 @@ func TestRetryUpdatePatchError(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryUpdate(context.Background(), brw, func(b *browserv1.Browser) { b.Labels = map[string]string{"k": "v"} })
 	if err == nil {
@@ -1422,7 +1822,7 @@ func TestRetryStatusUpdatePatchError(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryStatusUpdate(context.Background(), brw, func(b *browserv1.Browser) { b.Status.Phase = corev1.PodRunning })
 	if err == nil {
@@ -1434,7 +1834,7 @@ func TestDeleteBrowserNoFinalizer(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.deleteBrowser(context.Background(), brw)
 	if err != nil {
@@ -1456,7 +1856,7 @@ func TestDeleteBrowserDeleteError(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw)
 	cl := errorClient{Client: base, deleteErr: apierrors.NewInternalError(errors.New("delete"))}
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.deleteBrowser(context.Background(), brw)
 	if err == nil {
@@ -1480,7 +1880,7 @@ func TestHandleDeletionPodDeleteError(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw, pod)
 	cl := errorClient{Client: base, deleteErr: apierrors.NewInternalError(errors.New("delete"))}
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.handleDeletion(context.Background(), brw)
 	if err == nil {
@@ -1506,7 +1906,7 @@ func TestHandleDeletionDeleteSuccess(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.handleDeletion(context.Background(), brw)
 	if err != nil {
@@ -1533,7 +1933,7 @@ func TestHandleDeletionFailedPodGraceDelete(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.handleDeletion(context.Background(), brw)
 	if err != nil {
@@ -1556,7 +1956,7 @@ func TestHandleDeletionPodGetError(t *testing.T) {
 		},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, getPodErr: apierrors.NewInternalError(errors.New("pod"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, getPodErr: apierrors.NewInternalError(errors.New("pod"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.handleDeletion(context.Background(), brw)
 	if err != nil {
@@ -1576,7 +1976,7 @@ func TestHandleDeletionFinalizerRemoveError(t *testing.T) {
 		},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.handleDeletion(context.Background(), brw)
 	if err == nil {
@@ -1599,7 +1999,7 @@ func TestReconcileDeletionTimestamp(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1616,7 +2016,7 @@ func TestReconcileFinalizerAddError(t *testing.T) {
 		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1638,7 +2038,7 @@ func TestReconcileLabelUpdateError(t *testing.T) {
 		Spec: browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1658,7 +2058,7 @@ func TestReconcilePendingStatusUpdateError(t *testing.T) {
 		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1699,7 +2099,7 @@ func TestReconcilePendingTerminatedStatusUpdateError(t *testing.T) {
 		},
 	}
 	base := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1747,7 +2147,7 @@ func TestReconcilePendingCreationTimeoutStatusUpdateError(t *testing.T) {
 		},
 	}
 	base := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1791,7 +2191,7 @@ func TestReconcilePendingWaitingStatusUpdateError(t *testing.T) {
 		},
 	}
 	base := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, statusPatchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1816,7 +2216,7 @@ func TestReconcileHandleMissingPodCreateError(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw)
 	cl := errorClient{Client: base, createErr: apierrors.NewInternalError(errors.New("create"))}
-	r := NewBrowserReconciler(cl, cfgStore, scheme)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1847,7 +2247,7 @@ func TestReconcilePodDeletingDeleteBrowserError(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw, pod)
 	cl := errorClient{Client: base, deleteErr: apierrors.NewInternalError(errors.New("delete"))}
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1879,7 +2279,7 @@ func TestReconcilePodPendingWaitingDeleteError(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw, pod)
 	cl := errorClient{Client: base, deleteErr: apierrors.NewInternalError(errors.New("delete"))}
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -1902,7 +2302,7 @@ func TestUpdateBrowserStatusCriticalSidecar(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
@@ -1934,7 +2334,7 @@ func TestDeleteBrowserFinalizerSuccess(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.deleteBrowser(context.Background(), brw)
 	if err != nil {
@@ -1952,7 +2352,7 @@ func TestDeleteBrowserRetryUpdateError(t *testing.T) {
 		},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, patchErr: apierrors.NewInternalError(errors.New("patch"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.deleteBrowser(context.Background(), brw)
 	if err == nil {
@@ -1971,7 +2371,7 @@ func TestUpdateBrowserStatusCriticalAlreadyFailed(t *testing.T) {
 		Status: browserv1.BrowserStatus{Phase: corev1.PodFailed},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
@@ -2000,7 +2400,7 @@ func TestUpdateBrowserStatusNoContainerStatuses(t *testing.T) {
 		Status:     browserv1.BrowserStatus{Phase: corev1.PodPending},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
@@ -2025,7 +2425,7 @@ func TestReconcilePodFailedDeleteError(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw, pod)
 	cl := errorClient{Client: base, deleteErr: apierrors.NewInternalError(errors.New("delete"))}
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -2059,7 +2459,7 @@ func TestReconcilePodPendingPodInitializing(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw, pod)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -2072,7 +2472,7 @@ func TestReconcilePodPendingPodInitializing(t *testing.T) {
 func TestRetryStatusUpdateGetError(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	base := newBrowserClient(scheme)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, getErr: apierrors.NewBadRequest("bad")}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, getErr: apierrors.NewBadRequest("bad")}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryStatusUpdate(context.Background(), &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}, func(*browserv1.Browser) {})
 	if err == nil {
@@ -2095,7 +2495,7 @@ func TestUpdateBrowserStatusBrowserStatusChangedOnly(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
@@ -2130,7 +2530,7 @@ func TestUpdateBrowserStatusContainerStateChange(t *testing.T) {
 		},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
@@ -2158,7 +2558,7 @@ func TestUpdateBrowserStatusContainerStateChange(t *testing.T) {
 func TestReconcileBrowserGetError(t *testing.T) {
 	scheme := newBrowserScheme(t)
 	base := newBrowserClient(scheme)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, getErr: apierrors.NewBadRequest("bad")}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, getErr: apierrors.NewBadRequest("bad")}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -2175,7 +2575,7 @@ func TestReconcilePodGetError(t *testing.T) {
 		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
 	}
 	base := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(patchErrorClient{Client: base, getPodErr: apierrors.NewInternalError(errors.New("pod"))}, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(patchErrorClient{Client: base, getPodErr: apierrors.NewInternalError(errors.New("pod"))}, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
@@ -2193,7 +2593,7 @@ func TestUpdateBrowserStatusContainerStatusLengthChange(t *testing.T) {
 		Status:     browserv1.BrowserStatus{Phase: corev1.PodPending},
 	}
 	cl := newBrowserClient(scheme, brw)
-	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
@@ -2257,7 +2657,7 @@ func TestRetryUpdateConflictThenSuccess(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw)
 	c := &conflictClient{Client: base}
-	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryUpdate(context.Background(), brw, func(b *browserv1.Browser) {
 		if b.Labels == nil {
@@ -2283,7 +2683,7 @@ func TestRetryStatusUpdateConflictThenSuccess(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw)
 	c := &conflictClient{Client: base}
-	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryStatusUpdate(context.Background(), brw, func(b *browserv1.Browser) {
 		b.Status.Phase = corev1.PodRunning
@@ -2323,7 +2723,7 @@ func TestRetryUpdateMaxConflict(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw)
 	c := &alwaysConflictClient{Client: base}
-	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryUpdate(context.Background(), brw, func(b *browserv1.Browser) {
 		if b.Labels == nil {
@@ -2343,7 +2743,7 @@ func TestRetryStatusUpdateMaxConflict(t *testing.T) {
 	}
 	base := newBrowserClient(scheme, brw)
 	c := &alwaysConflictClient{Client: base}
-	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme)
+	r := NewBrowserReconciler(c, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
 
 	err := r.retryStatusUpdate(context.Background(), brw, func(b *browserv1.Browser) {
 		b.Status.Phase = corev1.PodRunning
@@ -2352,3 +2752,496 @@ func TestRetryStatusUpdateMaxConflict(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestApplyPodConditionsRunningPod(t *testing.T) {
+	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns", Generation: 2}}
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "browser", ImageID: "sha256:abc"},
+			},
+		},
+	}
+
+	if !applyPodConditions(brw, pod) {
+		t.Fatalf("expected conditions to change")
+	}
+	for _, conditionType := range []string{browserv1.BrowserScheduled, browserv1.BrowserImagePulled, browserv1.BrowserReady} {
+		if !meta.IsStatusConditionTrue(brw.Status.Conditions, conditionType) {
+			t.Fatalf("expected %s condition to be true, got %+v", conditionType, brw.Status.Conditions)
+		}
+	}
+	if brw.Status.ObservedGeneration != 2 {
+		t.Fatalf("expected observedGeneration 2, got %d", brw.Status.ObservedGeneration)
+	}
+	if applyPodConditions(brw, pod) {
+		t.Fatalf("expected no changes on second apply")
+	}
+}
+
+func TestApplyPodConditionsUnschedulableAndImagePullFailure(t *testing.T) {
+	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes"},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "browser", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}}},
+			},
+		},
+	}
+
+	applyPodConditions(brw, pod)
+
+	scheduled := meta.FindStatusCondition(brw.Status.Conditions, browserv1.BrowserScheduled)
+	if scheduled == nil || scheduled.Status != metav1.ConditionFalse || scheduled.Reason != "Unschedulable" {
+		t.Fatalf("unexpected Scheduled condition: %+v", scheduled)
+	}
+	pulled := meta.FindStatusCondition(brw.Status.Conditions, browserv1.BrowserImagePulled)
+	if pulled == nil || pulled.Status != metav1.ConditionFalse || pulled.Reason != "ImagePullBackOff" {
+		t.Fatalf("unexpected ImagePulled condition: %+v", pulled)
+	}
+	ready := meta.FindStatusCondition(brw.Status.Conditions, browserv1.BrowserReady)
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != browserv1.ReasonPodNotReady {
+		t.Fatalf("unexpected Ready condition: %+v", ready)
+	}
+}
+
+func TestHandleMissingPodConfigNotFoundSetsCondition(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	ready := meta.FindStatusCondition(got.Status.Conditions, browserv1.BrowserReady)
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != browserv1.ReasonConfigNotFound {
+		t.Fatalf("unexpected Ready condition: %+v", ready)
+	}
+}
+
+func TestReconcilePodPendingCreationTimeoutSetsCondition(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "b1",
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-podCreationTimeout - time.Second).UTC()),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "browser", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+		},
+	}
+	cl := newBrowserClient(scheme, brw, pod)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	ready := meta.FindStatusCondition(got.Status.Conditions, browserv1.BrowserReady)
+	if ready == nil || ready.Reason != browserv1.ReasonCreationTimeout {
+		t.Fatalf("unexpected Ready condition: %+v", ready)
+	}
+}
+
+func TestHandleDeletionSetsTerminatingCondition(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	now := metav1.Now()
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "b1",
+			Namespace:         "ns",
+			Finalizers:        []string{browserPodFinalizer},
+			DeletionTimestamp: &now,
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
+	cl := newBrowserClient(scheme, brw, pod)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(100))
+
+	if _, err := r.handleDeletion(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, browserv1.BrowserTerminating) {
+		t.Fatalf("expected Terminating condition, got %+v", got.Status.Conditions)
+	}
+}
+
+func expectEvent(t *testing.T, recorder *record.FakeRecorder, prefix string) {
+	t.Helper()
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.HasPrefix(event, prefix) {
+				return
+			}
+		default:
+			t.Fatalf("expected event %q", prefix)
+		}
+	}
+}
+
+func TestHandleMissingPodRecordsEvents(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "img"})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	missing := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b2", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "999"},
+	}
+	cl := newBrowserClient(scheme, brw, missing)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, corev1.EventTypeNormal+" "+eventReasonPodCreated)
+
+	if _, err := r.handleMissingPod(context.Background(), missing); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, corev1.EventTypeWarning+" "+eventReasonConfigNotFound)
+}
+
+func TestUpdateBrowserStatusRecordsRunningEvent(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Status:     browserv1.BrowserStatus{Phase: corev1.PodPending},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, recorder)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+
+	if _, err := r.updateBrowserStatus(context.Background(), brw, pod); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, corev1.EventTypeNormal+" "+eventReasonPodRunning)
+}
+
+func TestWithTimeoutsKeepsDefaultsForZeroValues(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	r := NewBrowserReconciler(newBrowserClient(scheme), store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(10)).
+		WithTimeouts(Timeouts{PodCreation: 15 * time.Minute, QuickCheck: time.Second})
+
+	if r.timeouts.PodCreation != 15*time.Minute || r.timeouts.QuickCheck != time.Second {
+		t.Fatalf("expected overrides to be applied, got %+v", r.timeouts)
+	}
+	if r.timeouts.PodDeletion != podDeletionTimeout || r.timeouts.PeriodicReconcile != periodicReconcile || r.timeouts.MediumRetry != mediumRetry {
+		t.Fatalf("expected defaults for zero values, got %+v", r.timeouts)
+	}
+}
+
+func TestReconcilePodPendingConfigStartupTimeout(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	startup := metav1.Duration{Duration: time.Minute}
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "img", StartupTimeout: &startup})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "b1",
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Minute).UTC()),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "browser", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+		},
+	}
+	cl := newBrowserClient(scheme, brw, pod)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(10))
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.Phase != corev1.PodFailed {
+		t.Fatalf("expected config startupTimeout to fail the Browser, got phase %s", got.Status.Phase)
+	}
+}
+
+func TestStartupAndDeletionTimeoutFallback(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	deletion := metav1.Duration{Duration: 30 * time.Second}
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "img", DeletionTimeout: &deletion})
+	r := NewBrowserReconciler(newBrowserClient(scheme), cfgStore, scheme, record.NewFakeRecorder(10)).
+		WithTimeouts(Timeouts{PodCreation: 7 * time.Minute})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	if got := r.startupTimeout(brw); got != 7*time.Minute {
+		t.Fatalf("expected flag startup timeout, got %v", got)
+	}
+	if got := r.deletionTimeout(brw); got != 30*time.Second {
+		t.Fatalf("expected config deletion timeout, got %v", got)
+	}
+}
+
+func TestReconcileMaxLifetimeExceededDeletesBrowser(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "b1",
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			Finalizers:        []string{browserPodFinalizer},
+			Labels: map[string]string{
+				"selenosis.io/browser":         "b1",
+				"selenosis.io/browser.name":    "chrome",
+				"selenosis.io/browser.version": "120",
+			},
+		},
+		Spec: browserv1.BrowserSpec{
+			BrowserName:    "chrome",
+			BrowserVersion: "120",
+			MaxLifetime:    &metav1.Duration{Duration: time.Hour},
+		},
+		Status: browserv1.BrowserStatus{Phase: corev1.PodRunning},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, recorder)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.DeletionTimestamp.IsZero() {
+		t.Fatalf("expected Browser to be marked for deletion")
+	}
+	if got.Status.Reason != browserv1.ReasonMaxLifetimeExceeded {
+		t.Fatalf("expected reason %s, got %s", browserv1.ReasonMaxLifetimeExceeded, got.Status.Reason)
+	}
+	expectEvent(t, recorder, corev1.EventTypeNormal+" "+eventReasonMaxLifetimeExceeded)
+}
+
+func TestRemainingLifetimeStartsAtAdmission(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	r := NewBrowserReconciler(newBrowserClient(scheme), store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(10))
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns", CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120", MaxLifetime: &metav1.Duration{Duration: time.Hour}},
+	}
+
+	if remaining, limited := r.remainingLifetime(brw); !limited || remaining > -time.Hour+time.Minute {
+		t.Fatalf("expected lifetime of a Browser without admission to start at creation, got %v %v", remaining, limited)
+	}
+
+	setCondition(brw, browserv1.BrowserAdmitted, metav1.ConditionFalse, browserv1.ReasonQueued, "")
+	if _, limited := r.remainingLifetime(brw); limited {
+		t.Fatalf("expected lifetime of a queued Browser not to run")
+	}
+
+	meta.SetStatusCondition(&brw.Status.Conditions, metav1.Condition{
+		Type:               browserv1.BrowserAdmitted,
+		Status:             metav1.ConditionTrue,
+		Reason:             browserv1.ReasonAdmitted,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+	})
+	if remaining, limited := r.remainingLifetime(brw); !limited || remaining < 49*time.Minute || remaining > 50*time.Minute {
+		t.Fatalf("expected lifetime to start at admission, got %v %v", remaining, limited)
+	}
+}
+
+func TestReconcileMaxLifetimeFromConfigCapsRequeue(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:       "img",
+		MaxLifetime: &metav1.Duration{Duration: time.Hour},
+	})
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "b1",
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour + 10*time.Second)),
+			Finalizers:        []string{browserPodFinalizer},
+			Labels: map[string]string{
+				"selenosis.io/browser":         "b1",
+				"selenosis.io/browser.name":    "chrome",
+				"selenosis.io/browser.version": "120",
+			},
+		},
+		Spec:   browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+		Status: browserv1.BrowserStatus{Phase: corev1.PodRunning},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	cl := newBrowserClient(scheme, brw, pod)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(10))
+
+	res, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.RequeueAfter <= 0 || res.RequeueAfter > 10*time.Second {
+		t.Fatalf("expected requeue capped by remaining lifetime, got %v", res.RequeueAfter)
+	}
+}
+
+func TestReconcileFailedBrowserTTLAfterFinished(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	ttl := int32(60)
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120", TTLSecondsAfterFinished: &ttl},
+		Status:     browserv1.BrowserStatus{Phase: corev1.PodFailed},
+	}
+	cl := newBrowserClient(scheme, brw)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(10))
+
+	res, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.RequeueAfter <= 0 || res.RequeueAfter > time.Minute {
+		t.Fatalf("expected requeue within ttl, got %v", res.RequeueAfter)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.CompletionTime == nil {
+		t.Fatalf("expected completion time to be set")
+	}
+}
+
+func TestReconcileFailedBrowserTTLExpiredDeletesBrowser(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	ttl := int32(60)
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "img", TTLSecondsAfterFinished: &ttl})
+	completed := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+		Status:     browserv1.BrowserStatus{Phase: corev1.PodFailed, CompletionTime: &completed},
+	}
+	cl := newBrowserClient(scheme, brw)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(10))
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected Browser to be deleted, got %v", err)
+	}
+}
+
+func TestHandleFinishedRecordsTTLExpired(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	ttl := int32(60)
+	completed := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns", Finalizers: []string{"test.selenosis.io/keep"}},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120", TTLSecondsAfterFinished: &ttl},
+		Status:     browserv1.BrowserStatus{Phase: corev1.PodFailed, Reason: browserv1.ReasonContainerFailed, CompletionTime: &completed},
+	}
+	setCondition(brw, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonContainerFailed, "")
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, recorder)
+
+	if _, err := r.handleFinished(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, corev1.EventTypeNormal+" "+eventReasonTTLExpired)
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "b1", Namespace: "ns"}, got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.DeletionTimestamp.IsZero() {
+		t.Fatalf("expected Browser to be marked for deletion")
+	}
+	if got.Status.Reason != browserv1.ReasonTTLExpired {
+		t.Fatalf("expected reason %s, got %s", browserv1.ReasonTTLExpired, got.Status.Reason)
+	}
+	if cond := meta.FindStatusCondition(got.Status.Conditions, browserv1.BrowserTerminating); cond == nil || cond.Reason != browserv1.ReasonTTLExpired {
+		t.Fatalf("expected Terminating condition with reason %s, got %+v", browserv1.ReasonTTLExpired, cond)
+	}
+	if cond := meta.FindStatusCondition(got.Status.Conditions, browserv1.BrowserReady); cond == nil || cond.Reason != browserv1.ReasonContainerFailed {
+		t.Fatalf("expected Ready condition to keep the failure reason, got %+v", cond)
+	}
+}
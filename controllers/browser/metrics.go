@@ -0,0 +1,165 @@
+package browser
+
+import (
+	"sync"
+	"time"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "selenosis"
+	metricsSubsystem = "browser"
+)
+
+var browserLabels = []string{"browserName", "browserVersion", "namespace"}
+
+var (
+	sessionsCreatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "sessions_created_total",
+		Help:      "Number of browser pods created.",
+	}, browserLabels)
+
+	sessionsFailedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "sessions_failed_total",
+		Help:      "Number of Browsers moved to Failed phase, by reason.",
+	}, append(append([]string{}, browserLabels...), "reason"))
+
+	sessionsDeletedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "sessions_deleted_total",
+		Help:      "Number of Browsers cleaned up by the controller.",
+	}, browserLabels)
+
+	startupDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "startup_duration_seconds",
+		Help:      "Time from Browser creation until its pod is Running.",
+		Buckets:   []float64{1, 2, 5, 10, 15, 20, 30, 45, 60, 90, 120, 180, 300, 600},
+	}, browserLabels)
+
+	browsersByPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "browsers",
+		Help:      "Number of live Browsers by phase.",
+	}, append(append([]string{}, browserLabels...), "phase"))
+
+	poolIdlePods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "pool_idle_pods",
+		Help:      "Number of idle pods kept in the warm pool after the last sync.",
+	}, browserLabels)
+
+	poolClaimsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "pool_claims_total",
+		Help:      "Number of warm pool claim attempts, by result (hit or miss).",
+	}, append(append([]string{}, browserLabels...), "result"))
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		sessionsCreatedTotal,
+		sessionsFailedTotal,
+		sessionsDeletedTotal,
+		startupDurationSeconds,
+		browsersByPhase,
+		poolIdlePods,
+		poolClaimsTotal,
+	)
+}
+
+func metricLabels(browser *browserv1.Browser) prometheus.Labels {
+	return prometheus.Labels{
+		"browserName":    browser.Spec.BrowserName,
+		"browserVersion": browser.Spec.BrowserVersion,
+		"namespace":      browser.Namespace,
+	}
+}
+
+func recordSessionCreated(browser *browserv1.Browser) {
+	sessionsCreatedTotal.With(metricLabels(browser)).Inc()
+}
+
+func recordSessionFailed(browser *browserv1.Browser, reason string) {
+	labels := metricLabels(browser)
+	labels["reason"] = reason
+	sessionsFailedTotal.With(labels).Inc()
+}
+
+func recordSessionDeleted(browser *browserv1.Browser) {
+	sessionsDeletedTotal.With(metricLabels(browser)).Inc()
+}
+
+func recordPoolClaim(browser *browserv1.Browser, hit bool) {
+	labels := metricLabels(browser)
+	labels["result"] = "miss"
+	if hit {
+		labels["result"] = "hit"
+	}
+	poolClaimsTotal.With(labels).Inc()
+}
+
+func recordStartupDuration(browser *browserv1.Browser, now time.Time) {
+	if browser.CreationTimestamp.IsZero() {
+		return
+	}
+	startupDurationSeconds.With(metricLabels(browser)).Observe(now.Sub(browser.CreationTimestamp.Time).Seconds())
+}
+
+// phaseTracker keeps browsersByPhase in sync with the last phase observed for each Browser.
+type phaseTracker struct {
+	mu     sync.Mutex
+	phases map[types.NamespacedName]prometheus.Labels
+}
+
+var browserPhases = &phaseTracker{phases: map[types.NamespacedName]prometheus.Labels{}}
+
+// observe moves the Browser to its current phase bucket.
+func (t *phaseTracker) observe(browser *browserv1.Browser) {
+	phase := browser.Status.Phase
+	if phase == "" {
+		phase = corev1.PodPending
+	}
+
+	labels := metricLabels(browser)
+	labels["phase"] = string(phase)
+
+	key := types.NamespacedName{Namespace: browser.Namespace, Name: browser.Name}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if old, ok := t.phases[key]; ok {
+		if old["phase"] == labels["phase"] {
+			return
+		}
+		browsersByPhase.With(old).Dec()
+	}
+	browsersByPhase.With(labels).Inc()
+	t.phases[key] = labels
+}
+
+// forget removes a Browser that no longer exists.
+func (t *phaseTracker) forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if old, ok := t.phases[key]; ok {
+		browsersByPhase.With(old).Dec()
+		delete(t.phases, key)
+	}
+}
@@ -0,0 +1,103 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestPhaseTrackerObserveAndForget(t *testing.T) {
+	tracker := &phaseTracker{phases: map[types.NamespacedName]prometheus.Labels{}}
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "tracked", Namespace: "metrics-ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	pending := browsersByPhase.WithLabelValues("chrome", "120", "metrics-ns", string(corev1.PodPending))
+	running := browsersByPhase.WithLabelValues("chrome", "120", "metrics-ns", string(corev1.PodRunning))
+	basePending, baseRunning := testutil.ToFloat64(pending), testutil.ToFloat64(running)
+
+	tracker.observe(brw)
+	tracker.observe(brw)
+	if got := testutil.ToFloat64(pending) - basePending; got != 1 {
+		t.Fatalf("expected 1 pending browser, got %v", got)
+	}
+
+	brw.Status.Phase = corev1.PodRunning
+	tracker.observe(brw)
+	if got := testutil.ToFloat64(pending) - basePending; got != 0 {
+		t.Fatalf("expected 0 pending browsers, got %v", got)
+	}
+	if got := testutil.ToFloat64(running) - baseRunning; got != 1 {
+		t.Fatalf("expected 1 running browser, got %v", got)
+	}
+
+	tracker.forget(types.NamespacedName{Name: "tracked", Namespace: "metrics-ns"})
+	if got := testutil.ToFloat64(running) - baseRunning; got != 0 {
+		t.Fatalf("expected 0 running browsers, got %v", got)
+	}
+	tracker.forget(types.NamespacedName{Name: "tracked", Namespace: "metrics-ns"})
+}
+
+func TestHandleMissingPodRecordsMetrics(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "metrics/chrome:121", &configv1.BrowserVersionConfigSpec{Image: "img"})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "metrics"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "121"},
+	}
+	missing := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b2", Namespace: "metrics"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "1"},
+	}
+	cl := newBrowserClient(scheme, brw, missing)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(10))
+
+	created := sessionsCreatedTotal.WithLabelValues("chrome", "121", "metrics")
+	failed := sessionsFailedTotal.WithLabelValues("chrome", "1", "metrics", browserv1.ReasonConfigNotFound)
+	baseCreated, baseFailed := testutil.ToFloat64(created), testutil.ToFloat64(failed)
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := r.handleMissingPod(context.Background(), missing); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(created) - baseCreated; got != 1 {
+		t.Fatalf("expected 1 created session, got %v", got)
+	}
+	if got := testutil.ToFloat64(failed) - baseFailed; got != 1 {
+		t.Fatalf("expected 1 failed session, got %v", got)
+	}
+}
+
+func TestRecordStartupDuration(t *testing.T) {
+	created := time.Now().Add(-42 * time.Second)
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "b1",
+			Namespace:         "startup",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: browserv1.BrowserSpec{BrowserName: "firefox", BrowserVersion: "118"},
+	}
+
+	recordStartupDuration(brw, created.Add(42*time.Second))
+	recordStartupDuration(&browserv1.Browser{}, time.Now())
+
+	if got := testutil.CollectAndCount(startupDurationSeconds, "selenosis_browser_startup_duration_seconds"); got == 0 {
+		t.Fatalf("expected startup duration to be observed")
+	}
+}
@@ -0,0 +1,288 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// syncNetworkPolicy keeps the NetworkPolicies of the Browser in line with its BrowserConfig. The policy is
+// ensured while the config restricts traffic, the policies left over after the config dropped networkPolicy
+// or switched its scope are removed, and the pod, nil before it is created, keeps the label of its shared policy.
+func (r *BrowserReconciler) syncNetworkPolicy(ctx context.Context, browser *browserv1.Browser, pod *corev1.Pod, cfg *configv1.BrowserNetworkPolicy) error {
+	if !restrictsTraffic(cfg) {
+		cfg = nil
+	}
+	if cfg != nil {
+		if err := r.ensureNetworkPolicy(ctx, browser, cfg); err != nil {
+			return err
+		}
+	}
+	if pod != nil {
+		if err := r.ensureNetworkPolicyPodLabel(ctx, browser, pod, cfg); err != nil {
+			return err
+		}
+	}
+	return r.removeStaleNetworkPolicies(ctx, browser, cfg)
+}
+
+// ensureNetworkPolicy creates or updates the NetworkPolicy restricting the browser pod before it starts.
+// Per-Browser policies are controlled by the Browser, shared policies list every Browser using them
+// as owner so they are garbage collected together with the last session.
+func (r *BrowserReconciler) ensureNetworkPolicy(ctx context.Context, browser *browserv1.Browser, cfg *configv1.BrowserNetworkPolicy) error {
+	log := logger.FromContext(ctx)
+
+	desired := buildNetworkPolicy(browser, cfg)
+	shared := cfg.Scope == configv1.NetworkPolicyScopeShared
+
+	existing := &networkingv1.NetworkPolicy{}
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	switch {
+	case errors.IsNotFound(err):
+		if err := r.client.Create(ctx, desired); err != nil {
+			return fmt.Errorf("create network policy %s: %w", desired.Name, err)
+		}
+		log.Info("Browser NetworkPolicy created", "networkPolicy", desired.Name)
+		r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonNetworkPolicyCreated, "Created network policy %s", desired.Name)
+		return nil
+	case err != nil:
+		return fmt.Errorf("get network policy %s: %w", desired.Name, err)
+	}
+
+	if shared && existing.Labels[browserv1.SelenosisNetworkPolicyLabelKey] != desired.Name {
+		return fmt.Errorf("network policy %s already exists and is not managed by the controller", desired.Name)
+	}
+	if !shared && !metav1.IsControlledBy(existing, browser) {
+		return fmt.Errorf("network policy %s already exists and is not owned by the Browser", desired.Name)
+	}
+
+	patch := client.MergeFromWithOptions(existing.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	changed := false
+	if shared && !hasOwner(existing, browser) {
+		existing.OwnerReferences = append(existing.OwnerReferences, desired.OwnerReferences...)
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		existing.Spec = desired.Spec
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.client.Patch(ctx, existing, patch); err != nil {
+		return fmt.Errorf("update network policy %s: %w", desired.Name, err)
+	}
+	log.Info("Browser NetworkPolicy updated", "networkPolicy", desired.Name)
+	return nil
+}
+
+// ensureNetworkPolicyPodLabel sets the selenosis.io/network-policy label of the pod to the shared policy
+// of the Browser and removes it otherwise, so a policy the Browser left no longer selects the pod.
+func (r *BrowserReconciler) ensureNetworkPolicyPodLabel(ctx context.Context, browser *browserv1.Browser, pod *corev1.Pod, cfg *configv1.BrowserNetworkPolicy) error {
+	var want string
+	if cfg != nil && cfg.Scope == configv1.NetworkPolicyScopeShared {
+		want = sharedNetworkPolicyName(browser.Spec.BrowserName, resolvedVersion(browser))
+	}
+	if pod.Labels[browserv1.SelenosisNetworkPolicyLabelKey] == want {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if want == "" {
+		delete(pod.Labels, browserv1.SelenosisNetworkPolicyLabelKey)
+	} else {
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		pod.Labels[browserv1.SelenosisNetworkPolicyLabelKey] = want
+	}
+	if err := r.client.Patch(ctx, pod, patch); err != nil {
+		return fmt.Errorf("update network policy label of pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// removeStaleNetworkPolicies removes the Browser from the controller-created NetworkPolicies it no longer
+// uses, cfg is nil when the config does not restrict traffic. The per-Browser policy is deleted, a shared
+// policy loses the Browser as owner and is deleted with its last one.
+func (r *BrowserReconciler) removeStaleNetworkPolicies(ctx context.Context, browser *browserv1.Browser, cfg *configv1.BrowserNetworkPolicy) error {
+	log := logger.FromContext(ctx)
+	shared := cfg != nil && cfg.Scope == configv1.NetworkPolicyScopeShared
+
+	if cfg == nil || shared {
+		np := &networkingv1.NetworkPolicy{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: browser.Namespace, Name: browser.Name}, np)
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("get network policy %s: %w", browser.Name, err)
+		case metav1.IsControlledBy(np, browser):
+			if err := r.deleteNetworkPolicy(ctx, browser, np); err != nil {
+				return err
+			}
+		}
+	}
+
+	var keep string
+	if shared {
+		keep = sharedNetworkPolicyName(browser.Spec.BrowserName, resolvedVersion(browser))
+	}
+	list := &networkingv1.NetworkPolicyList{}
+	if err := r.client.List(ctx, list, client.InNamespace(browser.Namespace), client.HasLabels{browserv1.SelenosisNetworkPolicyLabelKey}); err != nil {
+		return fmt.Errorf("list network policies: %w", err)
+	}
+	for i := range list.Items {
+		np := &list.Items[i]
+		if np.Name == keep || !hasOwner(np, browser) {
+			continue
+		}
+		if len(np.OwnerReferences) == 1 {
+			if err := r.deleteNetworkPolicy(ctx, browser, np); err != nil {
+				return err
+			}
+			continue
+		}
+
+		patch := client.MergeFromWithOptions(np.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		np.OwnerReferences = slices.DeleteFunc(np.OwnerReferences, func(ref metav1.OwnerReference) bool {
+			return ref.UID == browser.GetUID()
+		})
+		if err := r.client.Patch(ctx, np, patch); err != nil {
+			return fmt.Errorf("update network policy %s: %w", np.Name, err)
+		}
+		log.Info("Browser removed from shared NetworkPolicy", "networkPolicy", np.Name)
+	}
+	return nil
+}
+
+// deleteNetworkPolicy deletes a NetworkPolicy the Browser no longer uses, unless it changed since read,
+// e.g. a shared policy another Browser just joined.
+func (r *BrowserReconciler) deleteNetworkPolicy(ctx context.Context, browser *browserv1.Browser, np *networkingv1.NetworkPolicy) error {
+	err := r.client.Delete(ctx, np, client.Preconditions{UID: &np.UID, ResourceVersion: &np.ResourceVersion})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("delete network policy %s: %w", np.Name, err)
+	}
+	logger.FromContext(ctx).Info("Browser NetworkPolicy deleted", "networkPolicy", np.Name)
+	r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonNetworkPolicyDeleted, "Deleted network policy %s", np.Name)
+	return nil
+}
+
+// restrictsTraffic reports whether the network policy restricts ingress or egress. A policy without
+// either has no policy types, the API server would default it to deny all ingress of the session.
+func restrictsTraffic(cfg *configv1.BrowserNetworkPolicy) bool {
+	return cfg != nil && (cfg.IngressFrom != nil || cfg.Egress != nil)
+}
+
+// buildNetworkPolicy renders the NetworkPolicy of a Browser. Per-Browser policies select the pod
+// by the selenosis.io/browser label, shared ones by the selenosis.io/network-policy pod label.
+func buildNetworkPolicy(browser *browserv1.Browser, cfg *configv1.BrowserNetworkPolicy) *networkingv1.NetworkPolicy {
+	owner := metav1.OwnerReference{
+		APIVersion: browserv1.SchemeGroupVersion.String(),
+		Kind:       "Browser",
+		Name:       browser.GetName(),
+		UID:        browser.GetUID(),
+	}
+
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: browser.GetNamespace(),
+		},
+	}
+
+	if cfg.Scope == configv1.NetworkPolicyScopeShared {
+		name := sharedNetworkPolicyName(browser.Spec.BrowserName, resolvedVersion(browser))
+		np.Name = name
+		np.Labels = map[string]string{browserv1.SelenosisNetworkPolicyLabelKey: name}
+		np.OwnerReferences = []metav1.OwnerReference{owner}
+		np.Spec.PodSelector.MatchLabels = map[string]string{browserv1.SelenosisNetworkPolicyLabelKey: name}
+	} else {
+		np.Name = browser.GetName()
+		np.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(browser, browserv1.SchemeGroupVersion.WithKind("Browser")),
+		}
+		np.Spec.PodSelector.MatchLabels = map[string]string{browserv1.SelenosisBrowserLabelKey: browser.GetName()}
+	}
+
+	if cfg.IngressFrom != nil {
+		np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+		if len(*cfg.IngressFrom) > 0 {
+			np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: *cfg.IngressFrom}}
+		}
+	}
+
+	if cfg.Egress != nil {
+		np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		for _, rule := range *cfg.Egress {
+			egress := networkingv1.NetworkPolicyEgressRule{Ports: rule.Ports}
+			for _, cidr := range rule.CIDRs {
+				egress.To = append(egress.To, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+			}
+			np.Spec.Egress = append(np.Spec.Egress, egress)
+		}
+		if cfg.AllowDNS == nil || *cfg.AllowDNS {
+			np.Spec.Egress = append(np.Spec.Egress, dnsEgressRule())
+		}
+	}
+
+	return np
+}
+
+// networkPolicyPodLabels returns the pod labels a shared NetworkPolicy selects.
+func networkPolicyPodLabels(browser *browserv1.Browser, cfg *configv1.BrowserVersionConfigSpec) map[string]string {
+	if cfg.NetworkPolicy == nil || cfg.NetworkPolicy.Scope != configv1.NetworkPolicyScopeShared {
+		return nil
+	}
+	return map[string]string{
+		browserv1.SelenosisNetworkPolicyLabelKey: sharedNetworkPolicyName(browser.Spec.BrowserName, resolvedVersion(browser)),
+	}
+}
+
+// sharedNetworkPolicyName is a valid object name and label value for a browser version.
+func sharedNetworkPolicyName(browserName, version string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(browserName+"-"+version))
+
+	name = strings.Trim(name, "-")
+	if len(name) > 50 {
+		name = strings.TrimRight(name[:50], "-")
+	}
+	return name + "-browsers"
+}
+
+func dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	port := intstr.FromInt32(53)
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &udp, Port: &port},
+			{Protocol: &tcp, Port: &port},
+		},
+	}
+}
+
+func hasOwner(obj metav1.Object, owner metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
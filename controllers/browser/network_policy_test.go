@@ -0,0 +1,278 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func policyBrowser(name, uid string) *browserv1.Browser {
+	return &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", UID: types.UID("uid-" + uid)},
+		Spec:       browserv1.BrowserSpec{BrowserName: "Chrome", BrowserVersion: "latest"},
+		Status:     browserv1.BrowserStatus{ResolvedVersion: "120.0"},
+	}
+}
+
+func TestBuildNetworkPolicy(t *testing.T) {
+	router := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "selenosis"}}}
+	cfg := &configv1.BrowserNetworkPolicy{
+		IngressFrom: &[]networkingv1.NetworkPolicyPeer{router},
+		Egress:      &[]configv1.BrowserEgressRule{{CIDRs: []string{"10.0.0.0/8"}}},
+	}
+
+	np := buildNetworkPolicy(policyBrowser("b1", "1"), cfg)
+	if np.Name != "b1" || np.Spec.PodSelector.MatchLabels[browserv1.SelenosisBrowserLabelKey] != "b1" {
+		t.Fatalf("expected per-Browser policy selecting the Browser pod, got %+v", np)
+	}
+	if len(np.OwnerReferences) != 1 || np.OwnerReferences[0].Controller == nil || !*np.OwnerReferences[0].Controller {
+		t.Fatalf("expected per-Browser policy to be controlled by the Browser")
+	}
+	if len(np.Spec.PolicyTypes) != 2 || len(np.Spec.Ingress) != 1 || len(np.Spec.Ingress[0].From) != 1 {
+		t.Fatalf("unexpected ingress: %+v", np.Spec)
+	}
+	if len(np.Spec.Egress) != 2 || np.Spec.Egress[0].To[0].IPBlock.CIDR != "10.0.0.0/8" || np.Spec.Egress[1].Ports[0].Port.IntVal != 53 {
+		t.Fatalf("expected CIDR rule followed by DNS rule, got %+v", np.Spec.Egress)
+	}
+
+	allowDNS := false
+	cfg = &configv1.BrowserNetworkPolicy{Scope: configv1.NetworkPolicyScopeShared, Egress: &[]configv1.BrowserEgressRule{}, AllowDNS: &allowDNS}
+	np = buildNetworkPolicy(policyBrowser("b1", "1"), cfg)
+	if np.Name != "chrome-120-0-browsers" || np.Spec.PodSelector.MatchLabels[browserv1.SelenosisNetworkPolicyLabelKey] != np.Name {
+		t.Fatalf("expected shared policy per resolved version, got %+v", np.ObjectMeta)
+	}
+	if len(np.Spec.PolicyTypes) != 1 || np.Spec.PolicyTypes[0] != networkingv1.PolicyTypeEgress || len(np.Spec.Egress) != 0 {
+		t.Fatalf("expected deny-all egress without DNS, got %+v", np.Spec)
+	}
+}
+
+func TestBuildBrowserPodSharedNetworkPolicyLabel(t *testing.T) {
+	cfg := &configv1.BrowserVersionConfigSpec{
+		Image:         "img",
+		NetworkPolicy: &configv1.BrowserNetworkPolicy{Scope: configv1.NetworkPolicyScopeShared},
+	}
+	pod := buildBrowserPod(policyBrowser("b1", "1"), cfg, nil)
+	if pod.Labels[browserv1.SelenosisNetworkPolicyLabelKey] != "chrome-120-0-browsers" {
+		t.Fatalf("expected shared network policy label, got %v", pod.Labels)
+	}
+
+	cfg.NetworkPolicy.Scope = configv1.NetworkPolicyScopeBrowser
+	pod = buildBrowserPod(policyBrowser("b1", "1"), cfg, nil)
+	if _, ok := pod.Labels[browserv1.SelenosisNetworkPolicyLabelKey]; ok {
+		t.Fatalf("expected no shared label for per-Browser policies")
+	}
+}
+
+func TestEnsureSharedNetworkPolicyAddsOwners(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cl := newBrowserClient(scheme)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(10))
+	cfg := &configv1.BrowserNetworkPolicy{Scope: configv1.NetworkPolicyScopeShared, Egress: &[]configv1.BrowserEgressRule{}}
+
+	for _, b := range []*browserv1.Browser{policyBrowser("b1", "1"), policyBrowser("b2", "2"), policyBrowser("b2", "2")} {
+		if err := r.ensureNetworkPolicy(context.Background(), b, cfg); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	np := &networkingv1.NetworkPolicy{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "chrome-120-0-browsers"}, np); err != nil {
+		t.Fatalf("get network policy: %v", err)
+	}
+	if len(np.OwnerReferences) != 2 {
+		t.Fatalf("expected one owner per Browser, got %+v", np.OwnerReferences)
+	}
+}
+
+func TestEnsureNetworkPolicyRejectsForeignPolicy(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	foreign := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "chrome-120-0-browsers", Namespace: "ns"}}
+	cl := newBrowserClient(scheme, foreign)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(10))
+
+	err := r.ensureNetworkPolicy(context.Background(), policyBrowser("b1", "1"),
+		&configv1.BrowserNetworkPolicy{Scope: configv1.NetworkPolicyScopeShared})
+	if err == nil || !strings.Contains(err.Error(), "not managed") {
+		t.Fatalf("expected foreign policy to be rejected, got %v", err)
+	}
+}
+
+func TestSyncNetworkPolicyRemovesDroppedPolicy(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	brw := policyBrowser("b1", "1")
+	cfg := &configv1.BrowserNetworkPolicy{Egress: &[]configv1.BrowserEgressRule{}}
+	cl := newBrowserClient(scheme, buildNetworkPolicy(brw, cfg))
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, recorder)
+
+	// the BrowserConfig no longer sets networkPolicy
+	if err := r.syncNetworkPolicy(context.Background(), brw, nil, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal NetworkPolicyDeleted")
+
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &networkingv1.NetworkPolicy{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected dropped network policy to be deleted, got %v", err)
+	}
+}
+
+func TestSyncNetworkPolicySwitchesScope(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	b1, b2 := policyBrowser("b1", "1"), policyBrowser("b2", "2")
+	sharedCfg := &configv1.BrowserNetworkPolicy{Scope: configv1.NetworkPolicyScopeShared, Egress: &[]configv1.BrowserEgressRule{}}
+	shared := buildNetworkPolicy(b1, sharedCfg)
+	shared.OwnerReferences = append(shared.OwnerReferences, buildNetworkPolicy(b2, sharedCfg).OwnerReferences...)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "b1",
+		Namespace: "ns",
+		Labels:    map[string]string{browserv1.SelenosisNetworkPolicyLabelKey: shared.Name},
+	}}
+	cl := newBrowserClient(scheme, shared, pod)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(10))
+
+	cfg := &configv1.BrowserNetworkPolicy{Scope: configv1.NetworkPolicyScopeBrowser, Egress: &[]configv1.BrowserEgressRule{}}
+	if err := r.syncNetworkPolicy(context.Background(), b1, pod, cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &networkingv1.NetworkPolicy{}); err != nil {
+		t.Fatalf("expected per-Browser network policy, got %v", err)
+	}
+	np := &networkingv1.NetworkPolicy{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(shared), np); err != nil {
+		t.Fatalf("expected shared network policy to be kept for the other Browser, got %v", err)
+	}
+	if hasOwner(np, b1) || !hasOwner(np, b2) {
+		t.Fatalf("expected only the Browser that left to be removed as owner, got %+v", np.OwnerReferences)
+	}
+	got := &corev1.Pod{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(pod), got); err != nil {
+		t.Fatalf("get pod: %v", err)
+	}
+	if _, ok := got.Labels[browserv1.SelenosisNetworkPolicyLabelKey]; ok {
+		t.Fatalf("expected shared network policy label to be removed, got %v", got.Labels)
+	}
+
+	// the last Browser leaving deletes the shared policy
+	if err := r.syncNetworkPolicy(context.Background(), b2, nil, cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(shared), &networkingv1.NetworkPolicy{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected shared network policy to be deleted with its last owner, got %v", err)
+	}
+}
+
+func TestHandleMissingPodCreatesNetworkPolicyFirst(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:         "img",
+		NetworkPolicy: &configv1.BrowserNetworkPolicy{IngressFrom: &[]networkingv1.NetworkPolicyPeer{}},
+	})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal NetworkPolicyCreated")
+
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &networkingv1.NetworkPolicy{}); err != nil {
+		t.Fatalf("expected network policy to be created: %v", err)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected pod to be created: %v", err)
+	}
+}
+
+func TestReconcileRestoresDeletedNetworkPolicy(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:         "img",
+		NetworkPolicy: &configv1.BrowserNetworkPolicy{Egress: &[]configv1.BrowserEgressRule{}},
+	})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "b1",
+			Namespace:  "ns",
+			UID:        types.UID("uid-1"),
+			Finalizers: []string{browserPodFinalizer},
+			Labels: map[string]string{
+				"selenosis.io/browser":         "b1",
+				"selenosis.io/browser.name":    "chrome",
+				"selenosis.io/browser.version": "120",
+			},
+		},
+		Spec:   browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+		Status: browserv1.BrowserStatus{Phase: corev1.PodRunning},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	cl := newBrowserClient(scheme, brw, pod)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "ns", Name: "b1"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal NetworkPolicyCreated")
+
+	np := &networkingv1.NetworkPolicy{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, np); err != nil {
+		t.Fatalf("expected network policy to be restored: %v", err)
+	}
+	if len(np.Spec.PolicyTypes) != 1 || np.Spec.PolicyTypes[0] != networkingv1.PolicyTypeEgress {
+		t.Fatalf("expected egress policy, got %+v", np.Spec)
+	}
+}
+
+func TestHandleMissingPodSkipsEmptyNetworkPolicy(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:         "img",
+		NetworkPolicy: &configv1.BrowserNetworkPolicy{},
+	})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(10))
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	list := &networkingv1.NetworkPolicyList{}
+	if err := cl.List(context.Background(), list); err != nil {
+		t.Fatalf("list network policies: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Fatalf("expected no network policy denying the session ingress, got %+v", list.Items)
+	}
+}
@@ -0,0 +1,105 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+)
+
+// controllerLabels are managed by the controller and select Browser pods, they can never be overridden.
+var controllerLabels = []string{
+	browserv1.SelenosisBrowserLabelKey,
+	browserv1.SelenosisBrowserNameLabelKey,
+	browserv1.SelenosisBrowserVersionLabelKey,
+	browserv1.SelenosisPoolLabelKey,
+	browserv1.SelenosisNetworkPolicyLabelKey,
+}
+
+// optionsViolations lists the overrides of the selenosis options the BrowserConfig options policy
+// does not allow, in a stable order.
+func optionsViolations(opts *SelenosisOptions, policy *configv1.OptionsPolicy) []string {
+	if opts == nil {
+		return nil
+	}
+
+	var violations []string
+	for _, key := range sortedKeys(opts.Labels) {
+		if !labelAllowed(key, policy) {
+			violations = append(violations, fmt.Sprintf("label %s", key))
+		}
+	}
+
+	for _, name := range sortedKeys(opts.Containers) {
+		option := opts.Containers[name]
+		if !containerAllowed(name, policy) {
+			if len(option.Env) > 0 || option.Resources != nil {
+				violations = append(violations, fmt.Sprintf("container %s", name))
+			}
+			continue
+		}
+		for _, env := range sortedKeys(option.Env) {
+			if !envAllowed(env, policy) {
+				violations = append(violations, fmt.Sprintf("env %s of container %s", env, name))
+			}
+		}
+	}
+	return violations
+}
+
+// optionsDenied formats the violations of the options policy as an error, nil when there are none.
+func optionsDenied(violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s overrides not allowed: %s", browserv1.SelenosisOptionsAnnotationKey, strings.Join(violations, ", "))
+}
+
+func labelAllowed(key string, policy *configv1.OptionsPolicy) bool {
+	for _, label := range controllerLabels {
+		if key == label {
+			return false
+		}
+	}
+	if policy == nil {
+		return true
+	}
+	return allowed(key, policy.AllowedLabels, policy.DeniedLabels)
+}
+
+func containerAllowed(name string, policy *configv1.OptionsPolicy) bool {
+	if policy == nil {
+		return true
+	}
+	return allowed(name, policy.Containers, nil)
+}
+
+func envAllowed(name string, policy *configv1.OptionsPolicy) bool {
+	if policy == nil {
+		return true
+	}
+	return allowed(name, policy.AllowedEnv, policy.DeniedEnv)
+}
+
+// allowed matches a name against an allowlist, any name when empty, and a denylist that wins over it.
+func allowed(name string, allow, deny []string) bool {
+	if matchesAny(name, deny) {
+		return false
+	}
+	return len(allow) == 0 || matchesAny(name, allow)
+}
+
+// matchesAny matches exact names and patterns ending with * by prefix.
+func matchesAny(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package browser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func policyOptions() *SelenosisOptions {
+	return &SelenosisOptions{
+		Labels: map[string]string{"team": "qa", browserv1.SelenosisBrowserLabelKey: "other"},
+		Containers: map[string]ContainerOption{
+			browserContainerName: {Env: map[string]string{"SCREEN_RESOLUTION": "1920x1080", "SESSION_IDLE_TIMEOUT": "1h"}},
+			sidecarContainerName: {Env: map[string]string{"LOG_LEVEL": "debug"}},
+		},
+	}
+}
+
+func TestOptionsViolations(t *testing.T) {
+	got := optionsViolations(policyOptions(), nil)
+	if want := []string{"label selenosis.io/browser"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected controller labels to be protected without policy, got %v", got)
+	}
+	pool := &SelenosisOptions{Labels: map[string]string{browserv1.SelenosisPoolLabelKey: poolLabelIdle}}
+	if got := optionsViolations(pool, nil); !reflect.DeepEqual(got, []string{"label selenosis.io/pool"}) {
+		t.Fatalf("expected pool label to be protected without policy, got %v", got)
+	}
+
+	policy := &configv1.OptionsPolicy{
+		Containers:    []string{browserContainerName},
+		AllowedEnv:    []string{"SCREEN_*", "SESSION_*"},
+		DeniedEnv:     []string{"SESSION_IDLE_TIMEOUT"},
+		AllowedLabels: []string{"team"},
+	}
+	got = optionsViolations(policyOptions(), policy)
+	want := []string{
+		"label selenosis.io/browser",
+		"env SESSION_IDLE_TIMEOUT of container browser",
+		"container seleniferous",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	opts := &SelenosisOptions{Labels: map[string]string{"team": "qa"}}
+	if got := optionsViolations(opts, &configv1.OptionsPolicy{DeniedLabels: []string{"te*"}}); len(got) != 1 {
+		t.Fatalf("expected denied label prefix to match, got %v", got)
+	}
+}
+
+func TestApplySelenosisOptionsSkipsDeniedOverrides(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{browserv1.SelenosisBrowserLabelKey: "b1"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: browserContainerName},
+			{Name: sidecarContainerName},
+		}},
+	}
+	policy := &configv1.OptionsPolicy{Containers: []string{browserContainerName}, DeniedEnv: []string{"SESSION_IDLE_TIMEOUT"}}
+
+	applySelenosisOptions(pod, policyOptions(), policy)
+
+	if pod.Labels[browserv1.SelenosisBrowserLabelKey] != "b1" || pod.Labels["team"] != "qa" {
+		t.Fatalf("expected controller label kept and allowed label set, got %v", pod.Labels)
+	}
+	if env := pod.Spec.Containers[0].Env; len(env) != 1 || env[0].Name != "SCREEN_RESOLUTION" {
+		t.Fatalf("expected only allowed env on browser container, got %v", env)
+	}
+	if env := pod.Spec.Containers[1].Env; len(env) != 0 {
+		t.Fatalf("expected no env on denied container, got %v", env)
+	}
+}
+
+func TestHandleMissingPodDeniesOptions(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:         "img",
+		OptionsPolicy: &configv1.OptionsPolicy{DeniedEnv: []string{"SESSION_IDLE_TIMEOUT"}},
+	})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "b1",
+			Namespace:   "ns",
+			Annotations: map[string]string{browserv1.SelenosisOptionsAnnotationKey: `{"containers":{"browser":{"env":{"SESSION_IDLE_TIMEOUT":"1h"}}}}`},
+		},
+		Spec: browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Warning OptionsDenied")
+
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &corev1.Pod{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no pod to be created, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.Phase != corev1.PodFailed || got.Status.Reason != browserv1.ReasonOptionsDenied ||
+		!strings.Contains(got.Status.Message, "env SESSION_IDLE_TIMEOUT of container browser") {
+		t.Fatalf("expected Browser to fail with OptionsDenied, got %+v", got.Status)
+	}
+}
+
+func TestBrowserWebhookForbidsDeniedOptions(t *testing.T) {
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "chrome:120"})
+	w := NewBrowserWebhook(cfgStore)
+
+	_, err := w.ValidateCreate(context.Background(), webhookBrowser("120", `{"labels":{"selenosis.io/browser.version":"1"}}`))
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "Forbidden") || !strings.Contains(err.Error(), "label selenosis.io/browser.version") {
+		t.Fatalf("expected controller label override to be forbidden, got %v", err)
+	}
+}
@@ -0,0 +1,374 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultPoolSyncInterval is how often PoolManager refills warm pools by default
+	DefaultPoolSyncInterval = time.Second * 10
+
+	poolLabelIdle = "idle"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+
+// PoolManager keeps warm pools of idle browser pods filled according to BrowserConfig pool settings.
+// Idle pods carry the selenosis.io/pool=idle label and have no owner until a Browser claims them.
+type PoolManager struct {
+	client   client.Client
+	config   *store.BrowserConfigStore
+	interval time.Duration
+}
+
+func NewPoolManager(client client.Client, config *store.BrowserConfigStore, interval time.Duration) *PoolManager {
+	return &PoolManager{
+		client:   client,
+		config:   config,
+		interval: durationOrDefault(interval, DefaultPoolSyncInterval),
+	}
+}
+
+// NeedLeaderElection makes sure only the elected manager maintains the pools.
+func (p *PoolManager) NeedLeaderElection() bool {
+	return true
+}
+
+// Start syncs the pools every interval until the context is cancelled.
+func (p *PoolManager) Start(ctx context.Context) error {
+	log := logger.FromContext(ctx).WithName("browser-pool")
+	ctx = logger.IntoContext(ctx, log)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.sync(ctx); err != nil {
+			log.Error(err, "failed to sync browser pools")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+type poolKey struct {
+	namespace   string
+	browserName string
+	version     string
+}
+
+// sync refills every configured pool and removes idle pods of pools that are no longer configured.
+func (p *PoolManager) sync(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+
+	configs := map[poolKey]*configv1.BrowserVersionConfigSpec{}
+	for _, entry := range p.config.Entries() {
+		// pods of unwatched namespaces, e.g. the default config namespace, are not cached
+		if entry.Config == nil || entry.Config.Pool == nil || !p.config.Watches(entry.Namespace) {
+			continue
+		}
+		configs[poolKey{entry.Namespace, entry.BrowserName, entry.Version}] = entry.Config
+	}
+
+	pods := &corev1.PodList{}
+	if err := p.client.List(ctx, pods, client.MatchingLabels{browserv1.SelenosisPoolLabelKey: poolLabelIdle}); err != nil {
+		return fmt.Errorf("list pooled pods: %w", err)
+	}
+
+	members := map[poolKey][]*corev1.Pod{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		key := poolKey{pod.Namespace, pod.Labels[browserv1.SelenosisBrowserNameLabelKey], pod.Labels[browserv1.SelenosisBrowserVersionLabelKey]}
+		members[key] = append(members[key], pod)
+	}
+
+	var errs []error
+	for key, pods := range members {
+		if _, ok := configs[key]; ok {
+			continue
+		}
+		log.Info("removing idle pods of unconfigured pool", "namespace", key.namespace, "browserName", key.browserName, "browserVersion", key.version)
+		for _, pod := range pods {
+			errs = append(errs, p.deletePod(ctx, pod))
+		}
+		poolIdlePods.DeleteLabelValues(key.browserName, key.version, key.namespace)
+	}
+
+	for key, cfg := range configs {
+		errs = append(errs, p.syncPool(ctx, key, cfg, members[key]))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncPool recycles failed and expired idle pods, trims the pool to maxIdle and refills it to minIdle.
+func (p *PoolManager) syncPool(ctx context.Context, key poolKey, cfg *configv1.BrowserVersionConfigSpec, pods []*corev1.Pod) error {
+	log := logger.FromContext(ctx).WithValues("namespace", key.namespace, "browserName", key.browserName, "browserVersion", key.version)
+
+	pool := cfg.Pool
+	now := time.Now()
+
+	var errs []error
+	live := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded ||
+			idleExpired(pod, pool, now) || startupExpired(pod, cfg, now) {
+			errs = append(errs, p.deletePod(ctx, pod))
+			continue
+		}
+		live = append(live, pod)
+	}
+
+	sortOldestFirst(live)
+
+	maxIdle := poolMaxIdle(pool)
+	for int32(len(live)) > maxIdle {
+		errs = append(errs, p.deletePod(ctx, live[0]))
+		live = live[1:]
+	}
+
+	idle := int32(len(live))
+	for ; idle < pool.MinIdle; idle++ {
+		pod := buildPoolPod(key, cfg)
+		if err := p.client.Create(ctx, pod); err != nil {
+			errs = append(errs, fmt.Errorf("create pooled pod: %w", err))
+			break
+		}
+		log.Info("pooled browser pod created", "pod", pod.Name)
+	}
+
+	poolIdlePods.WithLabelValues(key.browserName, key.version, key.namespace).Set(float64(idle))
+	return utilerrors.NewAggregate(errs)
+}
+
+// deletePod deletes an idle pod of the cached list. Claiming a pod patches it, so the preconditions
+// make the delete fail instead of removing a pod a Browser claimed since the list.
+func (p *PoolManager) deletePod(ctx context.Context, pod *corev1.Pod) error {
+	log := logger.FromContext(ctx)
+
+	err := p.client.Delete(ctx, pod, client.Preconditions{UID: &pod.UID, ResourceVersion: &pod.ResourceVersion})
+	switch {
+	case errors.IsConflict(err):
+		log.Info("pooled browser pod changed since listed, not deleted", "pod", pod.Name, "namespace", pod.Namespace)
+		return nil
+	case err != nil && !errors.IsNotFound(err):
+		return fmt.Errorf("delete pooled pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	log.Info("pooled browser pod deleted", "pod", pod.Name, "namespace", pod.Namespace)
+	return nil
+}
+
+// buildPoolPod renders an idle, unowned browser pod for the pool.
+func buildPoolPod(key poolKey, cfg *configv1.BrowserVersionConfigSpec) *corev1.Pod {
+	template := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.namespace},
+		Spec: browserv1.BrowserSpec{
+			BrowserName:    key.browserName,
+			BrowserVersion: key.version,
+		},
+	}
+
+	pod := buildBrowserPod(template, cfg, nil)
+	pod.Name = ""
+	pod.GenerateName = poolPodPrefix(key)
+	pod.OwnerReferences = nil
+	pod.Spec.Hostname = ""
+
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[browserv1.SelenosisPoolLabelKey] = poolLabelIdle
+	pod.Labels[browserv1.SelenosisBrowserNameLabelKey] = key.browserName
+	pod.Labels[browserv1.SelenosisBrowserVersionLabelKey] = key.version
+
+	return pod
+}
+
+// poolPodPrefix builds a DNS label safe name prefix, e.g. chrome-120-0-pool-
+func poolPodPrefix(key poolKey) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(key.browserName+"-"+key.version))
+
+	name = strings.Trim(name, "-")
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-")
+	}
+	return name + "-pool-"
+}
+
+func poolMaxIdle(pool *configv1.Pool) int32 {
+	if pool.MaxIdle == nil || *pool.MaxIdle < pool.MinIdle {
+		return pool.MinIdle
+	}
+	return *pool.MaxIdle
+}
+
+func idleExpired(pod *corev1.Pod, pool *configv1.Pool, now time.Time) bool {
+	if pool.IdleTTL == nil || pool.IdleTTL.Duration <= 0 || pod.CreationTimestamp.IsZero() {
+		return false
+	}
+	return now.Sub(pod.CreationTimestamp.Time) > pool.IdleTTL.Duration
+}
+
+// startupExpired reports whether an idle pod stayed Pending longer than the startup timeout, e.g. in
+// ImagePullBackOff, so it is recycled instead of taking a pool slot forever.
+func startupExpired(pod *corev1.Pod, cfg *configv1.BrowserVersionConfigSpec, now time.Time) bool {
+	if pod.Status.Phase != corev1.PodPending || pod.CreationTimestamp.IsZero() {
+		return false
+	}
+	timeout := podCreationTimeout
+	if cfg.StartupTimeout != nil && cfg.StartupTimeout.Duration > 0 {
+		timeout = cfg.StartupTimeout.Duration
+	}
+	return now.Sub(pod.CreationTimestamp.Time) > timeout
+}
+
+func sortOldestFirst(pods []*corev1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+}
+
+// canClaimPooledPod reports whether the Browser can use a pre-provisioned pod,
+// container env overrides can't be applied to a running pod.
+func canClaimPooledPod(opts *SelenosisOptions) bool {
+	return opts == nil || len(opts.Containers) == 0
+}
+
+// claimPooledPod adopts a ready idle pod of the Browser's pool by re-labelling it
+// and attaching the Browser owner reference, returns nil when the pool is empty.
+func (r *BrowserReconciler) claimPooledPod(ctx context.Context, browser *browserv1.Browser, opts *SelenosisOptions, policy *configv1.OptionsPolicy) (*corev1.Pod, error) {
+	log := logger.FromContext(ctx)
+
+	// a previous reconcile may have claimed a pod without recording it in status
+	if pod, err := r.findClaimedPod(ctx, browser); err != nil || pod != nil {
+		return pod, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.client.List(ctx, pods,
+		client.InNamespace(browser.Namespace),
+		client.MatchingLabels{
+			browserv1.SelenosisPoolLabelKey:           poolLabelIdle,
+			browserv1.SelenosisBrowserNameLabelKey:    strings.ToLower(browser.Spec.BrowserName),
+			browserv1.SelenosisBrowserVersionLabelKey: strings.ToLower(resolvedVersion(browser)),
+		}); err != nil {
+		return nil, fmt.Errorf("list pooled pods: %w", err)
+	}
+
+	candidates := make([]*corev1.Pod, 0, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if ready := getPodCondition(pod, corev1.PodReady); ready == nil || ready.Status != corev1.ConditionTrue {
+			continue
+		}
+		candidates = append(candidates, pod)
+	}
+	sortOldestFirst(candidates)
+
+	for _, pod := range candidates {
+		before := pod.DeepCopy()
+		adoptPooledPod(pod, browser, opts, policy)
+
+		// optimistic lock makes sure concurrent Browsers never claim the same pod
+		if err := r.client.Patch(ctx, pod, client.MergeFromWithOptions(before, client.MergeFromWithOptimisticLock{})); err != nil {
+			if errors.IsConflict(err) || errors.IsNotFound(err) {
+				log.Info("pooled pod already taken, trying next", "pod", pod.Name)
+				continue
+			}
+			return nil, fmt.Errorf("claim pooled pod %s: %w", pod.Name, err)
+		}
+		return pod, nil
+	}
+
+	return nil, nil
+}
+
+// findClaimedPod returns the pooled pod already controlled by the Browser, if any.
+func (r *BrowserReconciler) findClaimedPod(ctx context.Context, browser *browserv1.Browser) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.client.List(ctx, pods,
+		client.InNamespace(browser.Namespace),
+		client.MatchingLabels{browserv1.SelenosisBrowserLabelKey: browser.Name}); err != nil {
+		return nil, fmt.Errorf("list claimed pods: %w", err)
+	}
+
+	for i := range pods.Items {
+		if metav1.IsControlledBy(&pods.Items[i], browser) {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// adoptPooledPod moves a pooled pod out of the pool and hands it over to the Browser.
+func adoptPooledPod(pod *corev1.Pod, browser *browserv1.Browser, opts *SelenosisOptions, policy *configv1.OptionsPolicy) {
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	delete(pod.Labels, browserv1.SelenosisPoolLabelKey)
+
+	for k, v := range browser.Labels {
+		pod.Labels[k] = v
+	}
+	pod.Labels[browserv1.SelenosisBrowserLabelKey] = browser.Name
+
+	for k, v := range browser.Annotations {
+		if k == browserv1.SelenosisOptionsAnnotationKey {
+			continue
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[k] = v
+	}
+
+	applySelenosisOptions(pod, opts, policy)
+
+	pod.OwnerReferences = append(pod.OwnerReferences,
+		*metav1.NewControllerRef(browser, browserv1.SchemeGroupVersion.WithKind("Browser")))
+}
+
+// resolvedVersion returns the concrete config version of the Browser, falling back to spec.browserVersion
+func resolvedVersion(browser *browserv1.Browser) string {
+	if browser.Status.ResolvedVersion != "" {
+		return browser.Status.ResolvedVersion
+	}
+	return browser.Spec.BrowserVersion
+}
+
+// podName returns the Browser pod name, the Browser name unless the pod was claimed from a warm pool
+func podName(browser *browserv1.Browser) string {
+	if browser.Status.PodName != "" {
+		return browser.Status.PodName
+	}
+	return browser.GetName()
+}
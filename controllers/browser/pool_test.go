@@ -0,0 +1,330 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func pooledPod(name, namespace string, created time.Time, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				browserv1.SelenosisPoolLabelKey:           poolLabelIdle,
+				browserv1.SelenosisBrowserNameLabelKey:    "chrome",
+				browserv1.SelenosisBrowserVersionLabelKey: "120",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func listPooledPods(t *testing.T, cl client.Client, namespace string) []corev1.Pod {
+	t.Helper()
+	pods := &corev1.PodList{}
+	if err := cl.List(context.Background(), pods, client.InNamespace(namespace),
+		client.MatchingLabels{browserv1.SelenosisPoolLabelKey: poolLabelIdle}); err != nil {
+		t.Fatalf("list pods: %v", err)
+	}
+	return pods.Items
+}
+
+func TestPoolPodPrefix(t *testing.T) {
+	got := poolPodPrefix(poolKey{namespace: "ns", browserName: "Chrome", version: "120.0"})
+	if got != "chrome-120-0-pool-" {
+		t.Fatalf("unexpected prefix: %q", got)
+	}
+
+	got = poolPodPrefix(poolKey{browserName: strings.Repeat("a", 60), version: "1"})
+	if len(got) > 46 {
+		t.Fatalf("expected prefix to be truncated, got %q", got)
+	}
+}
+
+func TestPoolMaxIdle(t *testing.T) {
+	if got := poolMaxIdle(&configv1.Pool{MinIdle: 2}); got != 2 {
+		t.Fatalf("expected maxIdle to default to minIdle, got %d", got)
+	}
+	if got := poolMaxIdle(&configv1.Pool{MinIdle: 2, MaxIdle: int32Ptr(1)}); got != 2 {
+		t.Fatalf("expected maxIdle below minIdle to be raised, got %d", got)
+	}
+	if got := poolMaxIdle(&configv1.Pool{MinIdle: 2, MaxIdle: int32Ptr(5)}); got != 5 {
+		t.Fatalf("expected maxIdle 5, got %d", got)
+	}
+}
+
+func TestBuildPoolPod(t *testing.T) {
+	cfg := &configv1.BrowserVersionConfigSpec{
+		Image:  "img",
+		Labels: &map[string]string{"team": "qa"},
+	}
+	pod := buildPoolPod(poolKey{namespace: "ns", browserName: "chrome", version: "120"}, cfg)
+
+	if pod.Name != "" || pod.GenerateName != "chrome-120-pool-" {
+		t.Fatalf("unexpected pod name: %q/%q", pod.Name, pod.GenerateName)
+	}
+	if len(pod.OwnerReferences) != 0 {
+		t.Fatalf("expected pooled pod to have no owner")
+	}
+	if pod.Spec.Hostname != "" {
+		t.Fatalf("expected empty hostname, got %q", pod.Spec.Hostname)
+	}
+	if pod.Labels[browserv1.SelenosisPoolLabelKey] != poolLabelIdle || pod.Labels["team"] != "qa" ||
+		pod.Labels[browserv1.SelenosisBrowserNameLabelKey] != "chrome" || pod.Labels[browserv1.SelenosisBrowserVersionLabelKey] != "120" {
+		t.Fatalf("unexpected labels: %v", pod.Labels)
+	}
+}
+
+func TestPoolManagerSyncRefillsTrimsAndRecycles(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "pool/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image: "img",
+		Pool:  &configv1.Pool{MinIdle: 2, MaxIdle: int32Ptr(3), IdleTTL: &metav1.Duration{Duration: time.Hour}},
+	})
+
+	now := time.Now()
+	expired := pooledPod("expired", "pool", now.Add(-2*time.Hour), true)
+	failed := pooledPod("failed", "pool", now, false)
+	failed.Status.Phase = corev1.PodFailed
+	fresh := pooledPod("fresh", "pool", now, true)
+
+	cl := newBrowserClient(scheme, expired, failed, fresh)
+	p := NewPoolManager(cl, cfgStore, 0)
+
+	if err := p.sync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pods := listPooledPods(t, cl, "pool")
+	if len(pods) != 2 {
+		t.Fatalf("expected pool refilled to 2 pods, got %d", len(pods))
+	}
+	for _, pod := range pods {
+		if pod.Name == "expired" || pod.Name == "failed" {
+			t.Fatalf("expected %s pod to be recycled", pod.Name)
+		}
+	}
+
+	// shrink the pool below the current size
+	setStoreConfig(t, cfgStore, "pool/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image: "img",
+		Pool:  &configv1.Pool{MinIdle: 1},
+	})
+	if err := p.sync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pods := listPooledPods(t, cl, "pool"); len(pods) != 1 || pods[0].Name != "fresh" {
+		t.Fatalf("expected oldest surplus pod to be removed, got %v", pods)
+	}
+}
+
+func TestPoolManagerSyncRemovesUnconfiguredPools(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "orphan/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "img"})
+
+	cl := newBrowserClient(scheme, pooledPod("idle", "orphan", time.Now(), true))
+	p := NewPoolManager(cl, cfgStore, time.Second)
+
+	if err := p.sync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pods := listPooledPods(t, cl, "orphan"); len(pods) != 0 {
+		t.Fatalf("expected idle pods of unconfigured pool to be removed, got %d", len(pods))
+	}
+}
+
+func TestPoolManagerSyncRecyclesStuckPendingPods(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "pending/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:          "img",
+		StartupTimeout: &metav1.Duration{Duration: time.Minute},
+		Pool:           &configv1.Pool{MinIdle: 2},
+	})
+
+	now := time.Now()
+	stuck := pooledPod("stuck", "pending", now.Add(-time.Hour), false)
+	stuck.Status.Phase = corev1.PodPending
+	starting := pooledPod("starting", "pending", now, false)
+	starting.Status.Phase = corev1.PodPending
+
+	cl := newBrowserClient(scheme, stuck, starting)
+	p := NewPoolManager(cl, cfgStore, 0)
+
+	if err := p.sync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	pods := listPooledPods(t, cl, "pending")
+	if len(pods) != 2 {
+		t.Fatalf("expected pool refilled to 2 pods, got %d", len(pods))
+	}
+	names := map[string]bool{}
+	for _, pod := range pods {
+		names[pod.Name] = true
+	}
+	if names["stuck"] || !names["starting"] {
+		t.Fatalf("expected only the stuck pending pod to be recycled, got %v", names)
+	}
+}
+
+func TestPoolManagerDeletePodSkipsClaimedPod(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cl := newBrowserClient(scheme, pooledPod("idle", "claimed", time.Now(), true))
+	p := NewPoolManager(cl, store.NewBrowserConfigStore(), 0)
+
+	listed := listPooledPods(t, cl, "claimed")[0]
+
+	// a Browser claims the pod after the pool listed it
+	claimed := listed.DeepCopy()
+	delete(claimed.Labels, browserv1.SelenosisPoolLabelKey)
+	if err := cl.Update(context.Background(), claimed); err != nil {
+		t.Fatalf("update pod: %v", err)
+	}
+
+	if err := p.deletePod(context.Background(), &listed); err != nil {
+		t.Fatalf("expected conflict to be skipped, got %v", err)
+	}
+	pod := &corev1.Pod{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "claimed", Name: "idle"}, pod); err != nil {
+		t.Fatalf("expected claimed pod to survive, got %v", err)
+	}
+}
+
+func TestHandleMissingPodClaimsPooledPod(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "claim/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image: "img",
+		Pool:  &configv1.Pool{MinIdle: 1},
+	})
+
+	now := time.Now()
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "b1",
+			Namespace:   "claim",
+			UID:         "uid-b1",
+			Labels:      map[string]string{"selenosis.io/browser": "b1"},
+			Annotations: map[string]string{browserv1.SelenosisOptionsAnnotationKey: `{"labels":{"session":"s1"}}`},
+		},
+		Spec: browserv1.BrowserSpec{BrowserName: "Chrome", BrowserVersion: "120"},
+	}
+	notReady := pooledPod("not-ready", "claim", now.Add(-time.Minute), false)
+	ready := pooledPod("ready", "claim", now, true)
+
+	cl := newBrowserClient(scheme, brw, notReady, ready)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal PodClaimed")
+
+	pod := &corev1.Pod{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "ready", Namespace: "claim"}, pod); err != nil {
+		t.Fatalf("get pod: %v", err)
+	}
+	if _, ok := pod.Labels[browserv1.SelenosisPoolLabelKey]; ok {
+		t.Fatalf("expected pool label to be removed")
+	}
+	if pod.Labels["selenosis.io/browser"] != "b1" || pod.Labels["session"] != "s1" {
+		t.Fatalf("unexpected labels on claimed pod: %v", pod.Labels)
+	}
+	if !metav1.IsControlledBy(pod, brw) {
+		t.Fatalf("expected claimed pod to be owned by Browser")
+	}
+
+	updated := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "b1", Namespace: "claim"}, updated); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if updated.Status.PodName != "ready" || podName(updated) != "ready" {
+		t.Fatalf("expected status podName to be recorded, got %q", updated.Status.PodName)
+	}
+
+	// a second pass finds the already claimed pod instead of taking another one
+	claimed, err := r.claimPooledPod(context.Background(), brw, nil, nil)
+	if err != nil || claimed == nil || claimed.Name != "ready" {
+		t.Fatalf("expected already claimed pod, got %v, %v", claimed, err)
+	}
+}
+
+func TestHandleMissingPodPoolSkippedForContainerOptions(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "claim/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image: "img",
+		Pool:  &configv1.Pool{MinIdle: 1},
+	})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "b2",
+			Namespace:   "claim",
+			Annotations: map[string]string{browserv1.SelenosisOptionsAnnotationKey: `{"containers":{"browser":{"env":{"A":"1"}}}}`},
+		},
+		Spec: browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw, pooledPod("ready", "claim", time.Now(), true))
+	r := NewBrowserReconciler(cl, cfgStore, scheme, record.NewFakeRecorder(10))
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pod := &corev1.Pod{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "b2", Namespace: "claim"}, pod); err != nil {
+		t.Fatalf("expected a dedicated pod to be created: %v", err)
+	}
+	if pods := listPooledPods(t, cl, "claim"); len(pods) != 1 {
+		t.Fatalf("expected pooled pod to stay idle")
+	}
+}
+
+func TestPoolManagerSyncSkipsUnwatchedNamespaces(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore().WithNamespaces("pool")
+	pool := &configv1.BrowserVersionConfigSpec{Image: "img", Pool: &configv1.Pool{MinIdle: 1}}
+	setStoreConfig(t, cfgStore, "pool/chrome:120", pool)
+	setStoreConfig(t, cfgStore, "defaults/chrome:120", pool)
+
+	cl := newBrowserClient(scheme)
+	p := NewPoolManager(cl, cfgStore, time.Second)
+
+	if err := p.sync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pods := listPooledPods(t, cl, "pool"); len(pods) != 1 {
+		t.Fatalf("expected pool of watched namespace to be filled, got %d", len(pods))
+	}
+	if pods := listPooledPods(t, cl, "defaults"); len(pods) != 0 {
+		t.Fatalf("expected pool of unwatched namespace to be skipped, got %d", len(pods))
+	}
+}
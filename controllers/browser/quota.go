@@ -0,0 +1,323 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// quotaLimit is a single limit of the namespace quota, Browsers with the same limit scope share it.
+type quotaLimit struct {
+	scope string
+	limit int32
+}
+
+// quotaUsage counts the Browsers holding a slot of each limit scope.
+type quotaUsage map[string]int32
+
+// hold counts the Browser against each of its limits.
+func (u quotaUsage) hold(limits []quotaLimit) {
+	for _, l := range limits {
+		u[l.scope]++
+	}
+}
+
+// exceeded returns the first of the limits the Browsers holding a slot exhaust, nil when none is.
+func (u quotaUsage) exceeded(limits []quotaLimit) *quotaLimit {
+	for i, l := range limits {
+		if u[l.scope] >= l.limit {
+			return &limits[i]
+		}
+	}
+	return nil
+}
+
+// admit records the Browser as admitted when it fits the quota of its namespace, otherwise it is
+// queued as Pending and requeued. Admitted Browsers keep their slot until they finish or are deleted.
+func (r *BrowserReconciler) admit(ctx context.Context, browser *browserv1.Browser) (bool, ctrl.Result, error) {
+	log := logger.FromContext(ctx)
+
+	if meta.IsStatusConditionTrue(browser.Status.Conditions, browserv1.BrowserAdmitted) {
+		return true, ctrl.Result{}, nil
+	}
+
+	reason, position, err := r.quotaExceeded(ctx, browser)
+	if err != nil {
+		log.Error(err, "failed to check Browser quota")
+		return false, ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+	}
+
+	if reason != "" {
+		message := fmt.Sprintf("queued at position %d, %s", position, reason)
+		cond := meta.FindStatusCondition(browser.Status.Conditions, browserv1.BrowserAdmitted)
+		if cond == nil || cond.Message != message || browser.Status.QueuePosition != position {
+			if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+				b.Status.Reason = browserv1.ReasonQueued
+				b.Status.Message = message
+				b.Status.QueuePosition = position
+				setCondition(b, browserv1.BrowserAdmitted, metav1.ConditionFalse, browserv1.ReasonQueued, message)
+				setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonQueued, message)
+			}); err != nil {
+				log.Error(err, "failed to record queued Browser")
+				return false, ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+			}
+			if cond == nil {
+				r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonQueued, "Browser queued at position %d: %s", position, reason)
+			}
+		}
+		log.Info("Browser queued", "reason", reason, "position", position)
+		return false, ctrl.Result{RequeueAfter: r.timeouts.PeriodicReconcile}, nil
+	}
+
+	queued := meta.IsStatusConditionFalse(browser.Status.Conditions, browserv1.BrowserAdmitted)
+	if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+		if b.Status.Reason == browserv1.ReasonQueued {
+			b.Status.Reason = ""
+			b.Status.Message = ""
+		}
+		b.Status.QueuePosition = 0
+		setCondition(b, browserv1.BrowserAdmitted, metav1.ConditionTrue, browserv1.ReasonAdmitted, "Browser admitted")
+		if queued {
+			setCondition(b, browserv1.BrowserReady, metav1.ConditionFalse, browserv1.ReasonPodPending, "waiting for browser pod")
+		}
+	}); err != nil {
+		log.Error(err, "failed to record admitted Browser")
+		return false, ctrl.Result{RequeueAfter: r.timeouts.MediumRetry}, err
+	}
+	setCondition(browser, browserv1.BrowserAdmitted, metav1.ConditionTrue, browserv1.ReasonAdmitted, "Browser admitted")
+	if queued {
+		r.recorder.Event(browser, corev1.EventTypeNormal, eventReasonAdmitted, "Browser admitted from the queue")
+		log.Info("Browser admitted from the queue")
+	}
+	return true, ctrl.Result{}, nil
+}
+
+// quotaExceeded returns which limit of the namespace quota the Browser does not fit, empty when it fits,
+// and its position in the admission queue. Admitted Browsers count against every limit, Browsers queued
+// ahead of it only when they fit their own limits and would be admitted first, see queueOrder.
+func (r *BrowserReconciler) quotaExceeded(ctx context.Context, browser *browserv1.Browser) (string, int32, error) {
+	quota := r.config.Quota(browser.Namespace)
+	limits := quotaLimits(browser, quota)
+	if len(limits) == 0 {
+		return "", 0, nil
+	}
+
+	// the cache may not hold the Browsers admitted by the last reconciles yet
+	var reader client.Reader = r.client
+	if r.apiReader != nil {
+		reader = r.apiReader
+	}
+	list := &browserv1.BrowserList{}
+	if err := reader.List(ctx, list, client.InNamespace(browser.Namespace)); err != nil {
+		return "", 0, fmt.Errorf("list Browsers: %w", err)
+	}
+
+	// nor the latest Browser
+	browsers := []*browserv1.Browser{browser}
+	for i := range list.Items {
+		if list.Items[i].Name != browser.Name {
+			browsers = append(browsers, &list.Items[i])
+		}
+	}
+
+	usage := quotaUsage{}
+	for _, b := range browsers[1:] {
+		if holdsQuota(b) {
+			usage.hold(quotaLimits(b, quota))
+		}
+	}
+
+	// a Browser ahead blocked by one of its own limits, e.g. its owner's, leaves its slot to the next one
+	var position int32
+	for _, b := range queueOrder(browsers, maxPriority(quota)) {
+		position++
+		if b == browser {
+			break
+		}
+		if ahead := quotaLimits(b, quota); usage.exceeded(ahead) == nil {
+			usage.hold(ahead)
+		}
+	}
+
+	if l := usage.exceeded(limits); l != nil {
+		return fmt.Sprintf("%s quota of %d Browsers reached", l.scope, l.limit), position, nil
+	}
+	return "", position, nil
+}
+
+// quotaLimits returns the limits of the namespace quota that apply to the Browser.
+func quotaLimits(browser *browserv1.Browser, quota *configv1.BrowserQuota) []quotaLimit {
+	if quota == nil {
+		return nil
+	}
+
+	var limits []quotaLimit
+	if quota.MaxBrowsers != nil {
+		limits = append(limits, quotaLimit{
+			scope: "namespace",
+			limit: *quota.MaxBrowsers,
+		})
+	}
+	if quota.MaxBrowsersPerVersion != nil {
+		browserName, version := strings.ToLower(browser.Spec.BrowserName), strings.ToLower(resolvedVersion(browser))
+		limits = append(limits, quotaLimit{
+			scope: fmt.Sprintf("%s:%s", browserName, version),
+			limit: *quota.MaxBrowsersPerVersion,
+		})
+	}
+	if owner := browser.Labels[browserv1.SelenosisOwnerLabelKey]; quota.MaxBrowsersPerOwner != nil && owner != "" {
+		limits = append(limits, quotaLimit{
+			scope: fmt.Sprintf("owner %s", owner),
+			limit: *quota.MaxBrowsersPerOwner,
+		})
+	}
+	return limits
+}
+
+// holdsQuota reports whether the Browser takes a slot of the quota. Browsers created before
+// quotas were enforced have no Admitted condition and take a slot once running.
+func holdsQuota(b *browserv1.Browser) bool {
+	if !activeBrowser(b) {
+		return false
+	}
+	cond := meta.FindStatusCondition(b.Status.Conditions, browserv1.BrowserAdmitted)
+	if cond == nil {
+		return b.Status.Phase == corev1.PodRunning
+	}
+	return cond.Status == metav1.ConditionTrue
+}
+
+// waitingForQuota reports whether the Browser is queued or not yet checked against the quota.
+func waitingForQuota(b *browserv1.Browser) bool {
+	return activeBrowser(b) && !holdsQuota(b)
+}
+
+func activeBrowser(b *browserv1.Browser) bool {
+	return b.DeletionTimestamp.IsZero() && b.Status.Phase != corev1.PodFailed && b.Status.Phase != corev1.PodSucceeded
+}
+
+// queueOrder sorts the waiting Browsers in admission order: higher spec.priority first, then the
+// selenosis.io/owner with the fewest admitted and earlier queued Browsers, then creation time.
+// Owners of the same priority take turns, so a large batch of one owner does not starve the others.
+// Priorities are capped at the quota maxPriority, see browserPriority.
+func queueOrder(browsers []*browserv1.Browser, max int32) []*browserv1.Browser {
+	load := map[string]int{}
+	var waiting []*browserv1.Browser
+	for _, b := range browsers {
+		switch {
+		case holdsQuota(b):
+			load[b.Labels[browserv1.SelenosisOwnerLabelKey]]++
+		case waitingForQuota(b):
+			waiting = append(waiting, b)
+		}
+	}
+
+	sort.SliceStable(waiting, func(i, j int) bool {
+		a, b := waiting[i], waiting[j]
+		if pa, pb := browserPriority(a, max), browserPriority(b, max); pa != pb {
+			return pa > pb
+		}
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+
+	// the turn of a Browser is the number of Browsers its owner has admitted or queued before it
+	turns := make(map[*browserv1.Browser]int, len(waiting))
+	for _, b := range waiting {
+		owner := b.Labels[browserv1.SelenosisOwnerLabelKey]
+		turns[b] = load[owner]
+		load[owner]++
+	}
+
+	sort.SliceStable(waiting, func(i, j int) bool {
+		a, b := waiting[i], waiting[j]
+		if pa, pb := browserPriority(a, max), browserPriority(b, max); pa != pb {
+			return pa > pb
+		}
+		return turns[a] < turns[b]
+	})
+	return waiting
+}
+
+// browserPriority returns the spec.priority of the Browser capped at max, Browsers created before
+// the quota maxPriority was lowered don't keep a priority the webhook now rejects.
+func browserPriority(b *browserv1.Browser, max int32) int32 {
+	if b.Spec.Priority == nil {
+		return 0
+	}
+	return min(*b.Spec.Priority, max)
+}
+
+// maxPriority returns the highest spec.priority the quota honours, 0 unless set.
+func maxPriority(quota *configv1.BrowserQuota) int32 {
+	if quota == nil || quota.MaxPriority == nil {
+		return 0
+	}
+	return *quota.MaxPriority
+}
+
+// queueChanged filters the Browser events that can move the admission queue of a namespace: a Browser
+// deleted, finishing or being deleted, admitted or queued. Other updates, e.g. pod status, are ignored.
+func queueChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return true },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldBrowser, ok := e.ObjectOld.(*browserv1.Browser)
+			if !ok {
+				return false
+			}
+			newBrowser, ok := e.ObjectNew.(*browserv1.Browser)
+			if !ok {
+				return false
+			}
+			return activeBrowser(oldBrowser) != activeBrowser(newBrowser) || admittedStatus(oldBrowser) != admittedStatus(newBrowser)
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// admittedStatus returns the status of the Admitted condition, empty when the Browser has none.
+func admittedStatus(b *browserv1.Browser) metav1.ConditionStatus {
+	if cond := meta.FindStatusCondition(b.Status.Conditions, browserv1.BrowserAdmitted); cond != nil {
+		return cond.Status
+	}
+	return ""
+}
+
+// queuedBrowsers enqueues the queued Browsers of a namespace, a finished or deleted Browser may free their slot.
+func (r *BrowserReconciler) queuedBrowsers(ctx context.Context, obj client.Object) []reconcile.Request {
+	if r.config.Quota(obj.GetNamespace()) == nil {
+		return nil
+	}
+
+	list := &browserv1.BrowserList{}
+	if err := r.client.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range list.Items {
+		b := &list.Items[i]
+		if b.Name == obj.GetName() || !meta.IsStatusConditionFalse(b.Status.Conditions, browserv1.BrowserAdmitted) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(b)})
+	}
+	return requests
+}
@@ -0,0 +1,363 @@
+package browser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func quotaStore(quota *configv1.BrowserQuota) *store.BrowserConfigStore {
+	cfgStore := store.NewBrowserConfigStore()
+	cfgStore.Add(&configv1.BrowserConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "ns"},
+		Spec: configv1.BrowserConfigSpec{
+			Quota: quota,
+			Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{
+				"chrome":  {"120": {Image: "chrome:120"}, "121": {Image: "chrome:121"}},
+				"firefox": {"118": {Image: "firefox:118"}},
+			},
+		},
+	})
+	return cfgStore
+}
+
+func quotaBrowser(name, version, owner string, created time.Time, admitted *bool) *browserv1.Browser {
+	b := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec:   browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: version},
+		Status: browserv1.BrowserStatus{Phase: corev1.PodPending},
+	}
+	if owner != "" {
+		b.Labels = map[string]string{browserv1.SelenosisOwnerLabelKey: owner}
+	}
+	if admitted != nil {
+		status, reason := metav1.ConditionFalse, browserv1.ReasonQueued
+		if *admitted {
+			status, reason = metav1.ConditionTrue, browserv1.ReasonAdmitted
+		}
+		setCondition(b, browserv1.BrowserAdmitted, status, reason, "")
+	}
+	return b
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	now := time.Now()
+	admitted, queued := true, false
+
+	tests := []struct {
+		name    string
+		quota   *configv1.BrowserQuota
+		browser *browserv1.Browser
+		want    string
+	}{
+		{
+			name:    "no quota",
+			browser: quotaBrowser("new", "120", "", now, nil),
+		},
+		{
+			name:    "namespace full",
+			quota:   &configv1.BrowserQuota{MaxBrowsers: int32Ptr(2)},
+			browser: quotaBrowser("new", "120", "", now, nil),
+			want:    "namespace quota of 2 Browsers reached",
+		},
+		{
+			name:    "older queued Browser goes first",
+			quota:   &configv1.BrowserQuota{MaxBrowsers: int32Ptr(3)},
+			browser: quotaBrowser("new", "120", "", now, nil),
+			want:    "namespace quota of 3 Browsers reached",
+		},
+		{
+			name:    "oldest queued Browser is admitted",
+			quota:   &configv1.BrowserQuota{MaxBrowsers: int32Ptr(3)},
+			browser: quotaBrowser("queued", "120", "alice", now.Add(-time.Minute), &queued),
+		},
+		{
+			name:    "version full",
+			quota:   &configv1.BrowserQuota{MaxBrowsersPerVersion: int32Ptr(1)},
+			browser: quotaBrowser("new", "120", "", now, nil),
+			want:    "chrome:120 quota of 1 Browsers reached",
+		},
+		{
+			name:    "other version fits",
+			quota:   &configv1.BrowserQuota{MaxBrowsersPerVersion: int32Ptr(1)},
+			browser: quotaBrowser("new", "121", "", now, nil),
+		},
+		{
+			name:    "owner full",
+			quota:   &configv1.BrowserQuota{MaxBrowsersPerOwner: int32Ptr(1)},
+			browser: quotaBrowser("new", "121", "bob", now, nil),
+			want:    "owner bob quota of 1 Browsers reached",
+		},
+		{
+			name:    "Browsers without owner are not limited per owner",
+			quota:   &configv1.BrowserQuota{MaxBrowsersPerOwner: int32Ptr(1)},
+			browser: quotaBrowser("new", "121", "", now, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finished := quotaBrowser("finished", "120", "bob", now.Add(-time.Hour), &admitted)
+			finished.Status.Phase = corev1.PodFailed
+			legacy := quotaBrowser("legacy", "119", "", now.Add(-time.Hour), nil)
+			legacy.Status.Phase = corev1.PodRunning
+
+			objs := []client.Object{
+				quotaBrowser("running", "120", "bob", now.Add(-time.Hour), &admitted),
+				legacy,
+				finished,
+				quotaBrowser("queued", "120", "alice", now.Add(-time.Minute), &queued),
+			}
+			cl := newBrowserClient(newBrowserScheme(t), objs...)
+			r := NewBrowserReconciler(cl, quotaStore(tt.quota), newBrowserScheme(t), record.NewFakeRecorder(10))
+
+			got, _, err := r.quotaExceeded(context.Background(), tt.browser)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQuotaExceededSkipsBlockedBrowsersAhead(t *testing.T) {
+	now := time.Now()
+	admitted, queued := true, false
+
+	// the queued Browser is ahead but blocked by its version limit, it must not hold the namespace slot
+	cl := newBrowserClient(newBrowserScheme(t),
+		quotaBrowser("running", "121", "", now.Add(-time.Hour), &admitted),
+		quotaBrowser("queued", "121", "bob", now.Add(-time.Minute), &queued),
+	)
+	quota := &configv1.BrowserQuota{MaxBrowsers: int32Ptr(2), MaxBrowsersPerVersion: int32Ptr(1)}
+	r := NewBrowserReconciler(cl, quotaStore(quota), newBrowserScheme(t), record.NewFakeRecorder(10))
+
+	got, position, err := r.quotaExceeded(context.Background(), quotaBrowser("new", "120", "", now, nil))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != "" || position != 2 {
+		t.Fatalf("expected Browser to be admitted at position 2, got %q at %d", got, position)
+	}
+}
+
+func TestAdmitCountsBrowsersMissingFromCache(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	now := time.Now()
+
+	first := quotaBrowser("first", "120", "", now.Add(-time.Minute), nil)
+	second := quotaBrowser("second", "120", "", now, nil)
+	api := newBrowserClient(scheme, first, second)
+
+	// the cache does not hold the first Browser yet when it is admitted
+	stale := newBrowserClient(scheme, second.DeepCopy())
+	cached := interceptor.NewClient(api.(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, _ client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			return stale.List(ctx, list, opts...)
+		},
+	})
+	r := NewBrowserReconciler(cached, quotaStore(&configv1.BrowserQuota{MaxBrowsers: int32Ptr(1)}), scheme, record.NewFakeRecorder(10)).
+		WithAPIReader(api)
+
+	for _, tc := range []struct {
+		name  string
+		admit bool
+	}{{"first", true}, {"second", false}} {
+		brw := &browserv1.Browser{}
+		if err := api.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: tc.name}, brw); err != nil {
+			t.Fatalf("get browser: %v", err)
+		}
+		ok, _, err := r.admit(context.Background(), brw)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if ok != tc.admit {
+			t.Fatalf("expected Browser %s admitted %v, got %v", tc.name, tc.admit, ok)
+		}
+	}
+}
+
+func TestHandleMissingPodQueuesAndAdmitsBrowser(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	now := time.Now()
+	admitted := true
+
+	running := quotaBrowser("running", "120", "", now.Add(-time.Hour), &admitted)
+	brw := quotaBrowser("b1", "120", "", now, nil)
+	cl := newBrowserClient(scheme, running, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, quotaStore(&configv1.BrowserQuota{MaxBrowsers: int32Ptr(1)}), scheme, recorder)
+
+	result, err := r.handleMissingPod(context.Background(), brw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.RequeueAfter != periodicReconcile {
+		t.Fatalf("expected queued Browser to be requeued, got %v", result.RequeueAfter)
+	}
+	expectEvent(t, recorder, "Normal Queued")
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.Phase != corev1.PodPending || got.Status.Reason != browserv1.ReasonQueued ||
+		!meta.IsStatusConditionFalse(got.Status.Conditions, browserv1.BrowserAdmitted) {
+		t.Fatalf("expected Browser to be queued, got %+v", got.Status)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &corev1.Pod{}); err == nil {
+		t.Fatalf("expected no pod for a queued Browser")
+	}
+
+	// the running Browser finishes and frees its slot
+	running.Status.Phase = corev1.PodFailed
+	if err := cl.Status().Update(context.Background(), running); err != nil {
+		t.Fatalf("update browser: %v", err)
+	}
+	if requests := r.queuedBrowsers(context.Background(), running); len(requests) != 1 || requests[0].Name != "b1" {
+		t.Fatalf("expected queued Browser to be enqueued, got %v", requests)
+	}
+
+	if _, err := r.handleMissingPod(context.Background(), got); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal Admitted")
+	expectEvent(t, recorder, "Normal PodCreated")
+
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.Reason != "" || !meta.IsStatusConditionTrue(got.Status.Conditions, browserv1.BrowserAdmitted) {
+		t.Fatalf("expected Browser to be admitted, got %+v", got.Status)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected pod for the admitted Browser, got %v", err)
+	}
+}
+
+func TestQueueOrder(t *testing.T) {
+	now := time.Now()
+	admitted, queued := true, false
+	priority := func(b *browserv1.Browser, p int32) *browserv1.Browser {
+		b.Spec.Priority = &p
+		return b
+	}
+
+	browsers := []*browserv1.Browser{
+		quotaBrowser("running-nightly", "120", "nightly", now.Add(-time.Hour), &admitted),
+		quotaBrowser("nightly-1", "120", "nightly", now.Add(-5*time.Minute), &queued),
+		quotaBrowser("nightly-2", "120", "nightly", now.Add(-4*time.Minute), &queued),
+		quotaBrowser("nightly-3", "120", "nightly", now.Add(-3*time.Minute), &queued),
+		quotaBrowser("team-1", "120", "team", now.Add(-2*time.Minute), &queued),
+		quotaBrowser("team-2", "120", "team", now.Add(-time.Minute), &queued),
+		priority(quotaBrowser("debug", "120", "dev", now, nil), 100),
+	}
+
+	var got []string
+	for _, b := range queueOrder(browsers, 100) {
+		got = append(got, b.Name)
+	}
+	want := []string{"debug", "team-1", "nightly-1", "team-2", "nightly-2", "nightly-3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// without a quota maxPriority the priority is capped at 0
+	got = nil
+	for _, b := range queueOrder(browsers, 0) {
+		got = append(got, b.Name)
+	}
+	want = []string{"team-1", "debug", "nightly-1", "team-2", "nightly-2", "nightly-3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestQueueChanged(t *testing.T) {
+	now := time.Now()
+	admitted, queued := true, false
+	p := queueChanged()
+
+	running := quotaBrowser("b", "120", "", now, &admitted)
+	running.Status.Phase = corev1.PodRunning
+	finished := running.DeepCopy()
+	finished.Status.Phase = corev1.PodSucceeded
+	deleting := running.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{Time: now}
+	podIP := running.DeepCopy()
+	podIP.Status.PodIP = "10.0.0.1"
+
+	waiting := quotaBrowser("b", "120", "", now, nil)
+	queuedBrowser := quotaBrowser("b", "120", "", now, &queued)
+	requeued := queuedBrowser.DeepCopy()
+	requeued.Status.QueuePosition = 2
+
+	for _, tc := range []struct {
+		name     string
+		old, new *browserv1.Browser
+		want     bool
+	}{
+		{"finished", running, finished, true},
+		{"deleting", running, deleting, true},
+		{"queued", waiting, queuedBrowser, true},
+		{"admitted", queuedBrowser, quotaBrowser("b", "120", "", now, &admitted), true},
+		{"pod status", running, podIP, false},
+		{"queue position", queuedBrowser, requeued, false},
+	} {
+		if got := p.Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new}); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	if p.Create(event.CreateEvent{Object: waiting}) {
+		t.Fatalf("expected create to be ignored")
+	}
+	if !p.Delete(event.DeleteEvent{Object: running}) {
+		t.Fatalf("expected delete to enqueue the queued Browsers")
+	}
+}
+
+func TestHandleMissingPodRecordsQueuePosition(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	now := time.Now()
+	admitted, queued := true, false
+
+	brw := quotaBrowser("debug", "120", "dev", now, nil)
+	brw.Spec.Priority = int32Ptr(10)
+	cl := newBrowserClient(scheme,
+		quotaBrowser("running", "120", "ci", now.Add(-time.Hour), &admitted),
+		quotaBrowser("nightly", "120", "ci", now.Add(-time.Minute), &queued),
+		brw,
+	)
+	r := NewBrowserReconciler(cl, quotaStore(&configv1.BrowserQuota{MaxBrowsers: int32Ptr(1), MaxPriority: int32Ptr(10)}), scheme, record.NewFakeRecorder(10))
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.QueuePosition != 1 || got.Status.Message != "queued at position 1, namespace quota of 1 Browsers reached" {
+		t.Fatalf("expected higher priority Browser at the head of the queue, got %+v", got.Status)
+	}
+}
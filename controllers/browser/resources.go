@@ -0,0 +1,164 @@
+package browser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// hasResourceOverrides reports whether the selenosis options override container resources.
+func hasResourceOverrides(opts *SelenosisOptions) bool {
+	if opts == nil {
+		return false
+	}
+	for _, option := range opts.Containers {
+		if option.Resources != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// boundResources checks the resource overrides of the selenosis options against the BrowserConfig
+// resource policy. Out-of-bounds values are clamped in place or rejected depending on the policy action,
+// the returned message lists the clamped values and is empty when nothing was adjusted.
+func boundResources(opts *SelenosisOptions, policy *configv1.ResourcePolicy) (string, error) {
+	if !hasResourceOverrides(opts) {
+		return "", nil
+	}
+	if policy == nil {
+		return "", fmt.Errorf("resource overrides are not allowed, the BrowserConfig defines no resourcePolicy")
+	}
+
+	var adjusted []string
+	for _, name := range sortedKeys(opts.Containers) {
+		option := opts.Containers[name]
+		if option.Resources == nil {
+			continue
+		}
+
+		for _, kind := range []struct {
+			field string
+			list  corev1.ResourceList
+		}{
+			{"requests", option.Resources.Requests},
+			{"limits", option.Resources.Limits},
+		} {
+			for _, res := range sortedKeys(kind.list) {
+				value := kind.list[res]
+				desc := fmt.Sprintf("container %s %s.%s %s", name, kind.field, res, value.String())
+
+				min, hasMin := policy.Min[res]
+				max, hasMax := policy.Max[res]
+				allowed := policy.Allowed[res]
+				if !hasMin && !hasMax && len(allowed) == 0 {
+					return "", fmt.Errorf("%s is not allowed, the resourcePolicy does not bound %s", desc, res)
+				}
+
+				bounded := value
+				if hasMin && value.Cmp(min) < 0 {
+					if policy.Action == configv1.ResourcePolicyReject {
+						return "", fmt.Errorf("%s is below the minimum %s", desc, min.String())
+					}
+					bounded = min
+				}
+				if hasMax && value.Cmp(max) > 0 {
+					if policy.Action == configv1.ResourcePolicyReject {
+						return "", fmt.Errorf("%s exceeds the maximum %s", desc, max.String())
+					}
+					bounded = max
+				}
+				if len(allowed) > 0 && !containsQuantity(allowed, bounded) {
+					if policy.Action == configv1.ResourcePolicyReject {
+						return "", fmt.Errorf("%s is not one of the allowed values %s", desc, formatQuantities(allowed))
+					}
+					bounded = nextAllowed(allowed, bounded)
+				}
+
+				if bounded.Cmp(value) != 0 {
+					kind.list[res] = bounded
+					adjusted = append(adjusted, fmt.Sprintf("%s clamped to %s", desc, bounded.String()))
+				}
+			}
+		}
+	}
+	return strings.Join(adjusted, ", "), nil
+}
+
+func containsQuantity(values []resource.Quantity, value resource.Quantity) bool {
+	for _, v := range values {
+		if v.Cmp(value) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// nextAllowed returns the smallest allowed value not below value, the largest one when value exceeds them all.
+func nextAllowed(allowed []resource.Quantity, value resource.Quantity) resource.Quantity {
+	next, largest := -1, 0
+	for i, v := range allowed {
+		if v.Cmp(allowed[largest]) > 0 {
+			largest = i
+		}
+		if v.Cmp(value) >= 0 && (next < 0 || v.Cmp(allowed[next]) < 0) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return allowed[largest]
+	}
+	return allowed[next]
+}
+
+func formatQuantities(values []resource.Quantity) string {
+	formatted := make([]string, 0, len(values))
+	for _, v := range values {
+		formatted = append(formatted, v.String())
+	}
+	return "[" + strings.Join(formatted, ", ") + "]"
+}
+
+// mergeResources overrides the requests and limits of a container per resource. Limits below
+// the resulting requests are raised to them unless the override sets the limit, then the request is lowered.
+func mergeResources(base corev1.ResourceRequirements, override corev1.ResourceRequirements) corev1.ResourceRequirements {
+	merged := *base.DeepCopy()
+	if len(override.Requests) > 0 && merged.Requests == nil {
+		merged.Requests = corev1.ResourceList{}
+	}
+	if len(override.Limits) > 0 && merged.Limits == nil {
+		merged.Limits = corev1.ResourceList{}
+	}
+	for res, value := range override.Requests {
+		merged.Requests[res] = value
+	}
+	for res, value := range override.Limits {
+		merged.Limits[res] = value
+	}
+
+	for res, request := range merged.Requests {
+		limit, ok := merged.Limits[res]
+		if !ok || request.Cmp(limit) <= 0 {
+			continue
+		}
+		if _, limited := override.Limits[res]; limited {
+			merged.Requests[res] = limit
+		} else {
+			merged.Limits[res] = request
+		}
+	}
+	return merged
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
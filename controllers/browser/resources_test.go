@@ -0,0 +1,236 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func memoryPolicy(action configv1.ResourcePolicyAction) *configv1.ResourcePolicy {
+	return &configv1.ResourcePolicy{
+		Min:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		Max:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+		Action: action,
+	}
+}
+
+func memoryOptions(request, limit string) *SelenosisOptions {
+	return &SelenosisOptions{Containers: map[string]ContainerOption{
+		browserContainerName: {Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(request)},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(limit)},
+		}},
+	}}
+}
+
+func TestBoundResources(t *testing.T) {
+	if msg, err := boundResources(&SelenosisOptions{Labels: map[string]string{"a": "b"}}, nil); msg != "" || err != nil {
+		t.Fatalf("expected options without resources to pass, got %q %v", msg, err)
+	}
+
+	if _, err := boundResources(memoryOptions("1Gi", "2Gi"), nil); err == nil || !strings.Contains(err.Error(), "no resourcePolicy") {
+		t.Fatalf("expected resource overrides without policy to be rejected, got %v", err)
+	}
+
+	opts := memoryOptions("1Gi", "2Gi")
+	if msg, err := boundResources(opts, memoryPolicy(configv1.ResourcePolicyClamp)); msg != "" || err != nil {
+		t.Fatalf("expected in-bounds overrides to pass unchanged, got %q %v", msg, err)
+	}
+
+	opts = memoryOptions("256Mi", "8Gi")
+	msg, err := boundResources(opts, memoryPolicy(configv1.ResourcePolicyClamp))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "container browser requests.memory 256Mi clamped to 512Mi, container browser limits.memory 8Gi clamped to 4Gi"
+	if msg != want {
+		t.Fatalf("expected %q, got %q", want, msg)
+	}
+	resources := opts.Containers[browserContainerName].Resources
+	if resources.Requests.Memory().String() != "512Mi" || resources.Limits.Memory().String() != "4Gi" {
+		t.Fatalf("expected values to be clamped in place, got %+v", resources)
+	}
+
+	_, err = boundResources(memoryOptions("1Gi", "8Gi"), memoryPolicy(configv1.ResourcePolicyReject))
+	if err == nil || err.Error() != "container browser limits.memory 8Gi exceeds the maximum 4Gi" {
+		t.Fatalf("expected rejection, got %v", err)
+	}
+}
+
+func TestBoundResourcesRejectsUnboundedResources(t *testing.T) {
+	opts := memoryOptions("1Gi", "2Gi")
+	opts.Containers[browserContainerName].Resources.Limits[corev1.ResourceEphemeralStorage] = resource.MustParse("10Gi")
+
+	_, err := boundResources(opts, memoryPolicy(configv1.ResourcePolicyClamp))
+	if want := "container browser limits.ephemeral-storage 10Gi is not allowed, the resourcePolicy does not bound ephemeral-storage"; err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestBoundResourcesAllowedValues(t *testing.T) {
+	policy := &configv1.ResourcePolicy{
+		Allowed: map[corev1.ResourceName][]resource.Quantity{
+			corev1.ResourceMemory: {resource.MustParse("4Gi"), resource.MustParse("1Gi"), resource.MustParse("2Gi")},
+		},
+		Action: configv1.ResourcePolicyClamp,
+	}
+
+	opts := memoryOptions("1500Mi", "8Gi")
+	msg, err := boundResources(opts, policy)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "container browser requests.memory 1500Mi clamped to 2Gi, container browser limits.memory 8Gi clamped to 4Gi"
+	if msg != want {
+		t.Fatalf("expected %q, got %q", want, msg)
+	}
+
+	if msg, err := boundResources(memoryOptions("1Gi", "2Gi"), policy); msg != "" || err != nil {
+		t.Fatalf("expected allowed values to pass unchanged, got %q %v", msg, err)
+	}
+
+	policy.Action = configv1.ResourcePolicyReject
+	_, err = boundResources(memoryOptions("1500Mi", "2Gi"), policy)
+	if want := "container browser requests.memory 1500Mi is not one of the allowed values [4Gi, 1Gi, 2Gi]"; err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestMergeResources(t *testing.T) {
+	base := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+
+	merged := mergeResources(base, corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	})
+	if merged.Requests.Memory().String() != "4Gi" || merged.Limits.Memory().String() != "4Gi" || merged.Requests.Cpu().String() != "500m" {
+		t.Fatalf("expected limit raised to the overridden request, got %+v", merged)
+	}
+	if base.Requests.Memory().String() != "1Gi" {
+		t.Fatalf("expected base resources not to be modified")
+	}
+
+	merged = mergeResources(base, corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+	})
+	if merged.Requests.Memory().String() != "512Mi" || merged.Limits.Memory().String() != "512Mi" {
+		t.Fatalf("expected request lowered to the overridden limit, got %+v", merged)
+	}
+}
+
+func TestHandleMissingPodClampsResources(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:          "img",
+		ResourcePolicy: memoryPolicy(configv1.ResourcePolicyClamp),
+	})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "b1",
+			Namespace:   "ns",
+			Annotations: map[string]string{browserv1.SelenosisOptionsAnnotationKey: `{"containers":{"browser":{"resources":{"limits":{"memory":"8Gi"}}}}}`},
+		},
+		Spec: browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal ResourcesClamped")
+
+	pod := &corev1.Pod{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, pod); err != nil {
+		t.Fatalf("expected pod to be created: %v", err)
+	}
+	if got := pod.Spec.Containers[0].Resources.Limits.Memory().String(); got != "4Gi" {
+		t.Fatalf("expected clamped memory limit, got %s", got)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, browserv1.BrowserResources)
+	if cond == nil || cond.Reason != browserv1.ReasonResourcesClamped || !strings.Contains(cond.Message, "clamped to 4Gi") {
+		t.Fatalf("expected clamp decision in status, got %+v", cond)
+	}
+}
+
+func TestHandleMissingPodRejectsResources(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:          "img",
+		ResourcePolicy: memoryPolicy(configv1.ResourcePolicyReject),
+	})
+
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "b1",
+			Namespace:   "ns",
+			Annotations: map[string]string{browserv1.SelenosisOptionsAnnotationKey: `{"containers":{"browser":{"resources":{"requests":{"memory":"128Mi"}}}}}`},
+		},
+		Spec: browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, cfgStore, scheme, recorder)
+
+	if _, err := r.handleMissingPod(context.Background(), brw); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Warning ResourcesRejected")
+
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, &corev1.Pod{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no pod to be created, got %v", err)
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.Phase != corev1.PodFailed || got.Status.Reason != browserv1.ReasonResourcesRejected {
+		t.Fatalf("expected Browser to fail with ResourcesRejected, got %+v", got.Status)
+	}
+	if !meta.IsStatusConditionFalse(got.Status.Conditions, browserv1.BrowserResources) {
+		t.Fatalf("expected Resources condition to be False")
+	}
+}
+
+func TestBrowserWebhookRejectsResources(t *testing.T) {
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{
+		Image:          "chrome:120",
+		ResourcePolicy: memoryPolicy(configv1.ResourcePolicyReject),
+	})
+	w := NewBrowserWebhook(cfgStore)
+
+	options := `{"containers":{"browser":{"resources":{"limits":{"memory":"2Gi"}}}}}`
+	if _, err := w.ValidateCreate(context.Background(), webhookBrowser("120", options)); err != nil {
+		t.Fatalf("expected in-bounds overrides to be accepted, got %v", err)
+	}
+
+	options = `{"containers":{"browser":{"resources":{"limits":{"memory":"16Gi"}}}}}`
+	_, err := w.ValidateCreate(context.Background(), webhookBrowser("120", options))
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "exceeds the maximum 4Gi") {
+		t.Fatalf("expected out-of-bounds overrides to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch
+
+// ensureService creates or updates the Service of a Browser exposing the ports of its pod
+// and publishes the Service name and DNS name in the Browser status.
+func (r *BrowserReconciler) ensureService(ctx context.Context, browser *browserv1.Browser, pod *corev1.Pod, cfg *configv1.BrowserService) error {
+	log := logger.FromContext(ctx)
+
+	desired := buildBrowserService(browser, pod, cfg)
+
+	existing := &corev1.Service{}
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	switch {
+	case errors.IsNotFound(err):
+		if err := r.client.Create(ctx, desired); err != nil {
+			return fmt.Errorf("create service %s: %w", desired.Name, err)
+		}
+		log.Info("Browser Service created", "service", desired.Name)
+		r.recorder.Eventf(browser, corev1.EventTypeNormal, eventReasonServiceCreated, "Created service %s", desired.Name)
+	case err != nil:
+		return fmt.Errorf("get service %s: %w", desired.Name, err)
+	default:
+		if !metav1.IsControlledBy(existing, browser) {
+			return fmt.Errorf("service %s already exists and is not owned by the Browser", desired.Name)
+		}
+		if serviceUpToDate(existing, desired) {
+			break
+		}
+		patch := client.MergeFrom(existing.DeepCopy())
+		existing.Labels = desired.Labels
+		existing.Annotations = desired.Annotations
+		existing.Spec.Selector = desired.Spec.Selector
+		existing.Spec.Ports = desired.Spec.Ports
+		if err := r.client.Patch(ctx, existing, patch); err != nil {
+			return fmt.Errorf("update service %s: %w", desired.Name, err)
+		}
+		log.Info("Browser Service updated", "service", desired.Name)
+	}
+
+	dnsName := serviceDNSName(desired)
+	if browser.Status.ServiceName == desired.Name && browser.Status.DNSName == dnsName {
+		return nil
+	}
+	if err := r.retryStatusUpdate(ctx, browser, func(b *browserv1.Browser) {
+		b.Status.ServiceName = desired.Name
+		b.Status.DNSName = dnsName
+	}); err != nil {
+		return err
+	}
+	browser.Status.ServiceName = desired.Name
+	browser.Status.DNSName = dnsName
+	return nil
+}
+
+// buildBrowserService renders the Service selecting the browser pod through the selenosis.io/browser label.
+func buildBrowserService(browser *browserv1.Browser, pod *corev1.Pod, cfg *configv1.BrowserService) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName(browser),
+			Namespace: browser.GetNamespace(),
+			Labels: map[string]string{
+				browserv1.SelenosisBrowserLabelKey:        browser.GetName(),
+				browserv1.SelenosisBrowserNameLabelKey:    browser.Spec.BrowserName,
+				browserv1.SelenosisBrowserVersionLabelKey: versionLabelValue(browser),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(browser, browserv1.SchemeGroupVersion.WithKind("Browser")),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{browserv1.SelenosisBrowserLabelKey: browser.GetName()},
+			Ports:    servicePorts(pod),
+		},
+	}
+
+	// a ClusterIP Service needs a port, a pod exposing none still gets a DNS name through a headless one
+	if cfg.Type != configv1.BrowserServiceClusterIP || len(svc.Spec.Ports) == 0 {
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+	}
+
+	for k, v := range cfg.Labels {
+		svc.Labels[k] = v
+	}
+	if len(cfg.Annotations) > 0 {
+		svc.Annotations = make(map[string]string, len(cfg.Annotations))
+		for k, v := range cfg.Annotations {
+			svc.Annotations[k] = v
+		}
+	}
+
+	return svc
+}
+
+// servicePorts exposes every port of the pod containers and native sidecars, unnamed or clashing
+// port names are replaced by <container>-<port>.
+func servicePorts(pod *corev1.Pod) []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	names := map[string]struct{}{}
+	seen := map[string]struct{}{}
+
+	for _, c := range runningContainers(pod) {
+		for _, p := range c.Ports {
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+
+			key := fmt.Sprintf("%d/%s", p.ContainerPort, protocol)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			name := strings.ToLower(p.Name)
+			if _, ok := names[name]; ok || name == "" {
+				name = fmt.Sprintf("%s-%d", strings.ToLower(c.Name), p.ContainerPort)
+			}
+			names[name] = struct{}{}
+
+			ports = append(ports, corev1.ServicePort{
+				Name:       name,
+				Protocol:   protocol,
+				Port:       p.ContainerPort,
+				TargetPort: intstr.FromInt32(p.ContainerPort),
+			})
+		}
+	}
+	return ports
+}
+
+// serviceName derives a valid Service name (DNS-1035 label) from the Browser name.
+func serviceName(browser *browserv1.Browser) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(browser.GetName()))
+
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		name = "browser-" + name
+	}
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.TrimRight(name, "-")
+}
+
+func serviceDNSName(svc *corev1.Service) string {
+	return fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace)
+}
+
+func serviceUpToDate(existing, desired *corev1.Service) bool {
+	return equality.Semantic.DeepEqual(existing.Labels, desired.Labels) &&
+		equality.Semantic.DeepEqual(existing.Annotations, desired.Annotations) &&
+		equality.Semantic.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) &&
+		equality.Semantic.DeepEqual(existing.Spec.Ports, desired.Spec.Ports)
+}
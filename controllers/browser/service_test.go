@@ -0,0 +1,153 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func servicePod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "browser", Ports: []corev1.ContainerPort{{ContainerPort: 5900}}},
+				{Name: "seleniferous", Ports: []corev1.ContainerPort{
+					{Name: "http", ContainerPort: 4445},
+					{Name: "http", ContainerPort: 4446, Protocol: corev1.ProtocolUDP},
+					{ContainerPort: 5900},
+				}},
+			},
+		},
+	}
+}
+
+func TestServiceName(t *testing.T) {
+	cases := map[string]string{
+		"b1":                                   "b1",
+		"1568aeff-a91a-449b-834b-d79bf2d6d623": "browser-1568aeff-a91a-449b-834b-d79bf2d6d623",
+		"my.browser":                           "my-browser",
+		strings.Repeat("a", 70):                strings.Repeat("a", 63),
+	}
+	for name, want := range cases {
+		got := serviceName(&browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		if got != want {
+			t.Fatalf("serviceName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestServicePorts(t *testing.T) {
+	ports := servicePorts(servicePod())
+	if len(ports) != 3 {
+		t.Fatalf("expected duplicate ports to be dropped, got %+v", ports)
+	}
+
+	want := []struct {
+		name     string
+		port     int32
+		protocol corev1.Protocol
+	}{
+		{"browser-5900", 5900, corev1.ProtocolTCP},
+		{"http", 4445, corev1.ProtocolTCP},
+		{"seleniferous-4446", 4446, corev1.ProtocolUDP},
+	}
+	for i, w := range want {
+		p := ports[i]
+		if p.Name != w.name || p.Port != w.port || p.Protocol != w.protocol || p.TargetPort.IntVal != w.port {
+			t.Fatalf("unexpected port %d: %+v", i, p)
+		}
+	}
+}
+
+func TestEnsureServiceCreatesHeadlessService(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns", UID: "uid-1"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: "120"},
+	}
+	cl := newBrowserClient(scheme, brw)
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, recorder)
+
+	cfg := &configv1.BrowserService{Annotations: map[string]string{"mesh": "on"}}
+	if err := r.ensureService(context.Background(), brw, servicePod(), cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectEvent(t, recorder, "Normal ServiceCreated")
+
+	svc := &corev1.Service{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, svc); err != nil {
+		t.Fatalf("get service: %v", err)
+	}
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone || svc.Annotations["mesh"] != "on" || len(svc.Spec.Ports) != 3 {
+		t.Fatalf("unexpected service: %+v", svc)
+	}
+	if svc.Spec.Selector[browserv1.SelenosisBrowserLabelKey] != "b1" || !metav1.IsControlledBy(svc, brw) {
+		t.Fatalf("expected service to select and be owned by the Browser")
+	}
+
+	got := &browserv1.Browser{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(brw), got); err != nil {
+		t.Fatalf("get browser: %v", err)
+	}
+	if got.Status.ServiceName != "b1" || got.Status.DNSName != "b1.ns.svc" {
+		t.Fatalf("unexpected status: %+v", got.Status)
+	}
+
+	// an existing Service is patched when the config changes
+	cfg.Annotations = nil
+	if err := r.ensureService(context.Background(), brw, servicePod(), cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "b1"}, svc); err != nil {
+		t.Fatalf("get service: %v", err)
+	}
+	if _, ok := svc.Annotations["mesh"]; ok {
+		t.Fatalf("expected annotations to be updated, got %v", svc.Annotations)
+	}
+}
+
+func TestBuildBrowserService(t *testing.T) {
+	brw := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: ">=118 <121"},
+	}
+	cfg := &configv1.BrowserService{Type: configv1.BrowserServiceClusterIP}
+
+	svc := buildBrowserService(brw, servicePod(), cfg)
+	if svc.Spec.ClusterIP != "" || len(svc.Spec.Ports) != 3 {
+		t.Fatalf("expected ClusterIP service exposing the pod ports, got %+v", svc.Spec)
+	}
+	if errs := validation.IsValidLabelValue(svc.Labels[browserv1.SelenosisBrowserVersionLabelKey]); len(errs) > 0 {
+		t.Fatalf("expected valid version label for a version query, got %v", errs)
+	}
+
+	// the API server rejects a ClusterIP Service without ports
+	svc = buildBrowserService(brw, &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "browser"}}}}, cfg)
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone || len(svc.Spec.Ports) != 0 {
+		t.Fatalf("expected headless service for a pod without ports, got %+v", svc.Spec)
+	}
+}
+
+func TestEnsureServiceRejectsForeignService(t *testing.T) {
+	scheme := newBrowserScheme(t)
+	brw := &browserv1.Browser{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns", UID: "uid-1"}}
+	foreign := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"}}
+	cl := newBrowserClient(scheme, brw, foreign)
+	r := NewBrowserReconciler(cl, store.NewBrowserConfigStore(), scheme, record.NewFakeRecorder(10))
+
+	err := r.ensureService(context.Background(), brw, servicePod(), &configv1.BrowserService{Type: configv1.BrowserServiceClusterIP})
+	if err == nil || !strings.Contains(err.Error(), "not owned") {
+		t.Fatalf("expected foreign service to be rejected, got %v", err)
+	}
+}
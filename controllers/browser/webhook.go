@@ -0,0 +1,217 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	"github.com/alcounit/browser-controller/store"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate-selenosis-io-v1-browser,mutating=true,failurePolicy=fail,sideEffects=None,groups=selenosis.io,resources=browsers,verbs=create;update,versions=v1,name=mbrowser.selenosis.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-selenosis-io-v1-browser,mutating=false,failurePolicy=fail,sideEffects=None,groups=selenosis.io,resources=browsers,verbs=create;update,versions=v1,name=vbrowser.selenosis.io,admissionReviewVersions=v1
+
+// BrowserWebhook defaults the selenosis.io/browser labels of Browsers and rejects Browsers without
+// a matching BrowserConfig entry, with malformed selenosis options or overrides its policies refuse.
+// Browsers of namespaces the controller does not watch are left to the controller watching them.
+type BrowserWebhook struct {
+	config *store.BrowserConfigStore
+}
+
+var (
+	_ admission.CustomDefaulter = &BrowserWebhook{}
+	_ admission.CustomValidator = &BrowserWebhook{}
+)
+
+func NewBrowserWebhook(config *store.BrowserConfigStore) *BrowserWebhook {
+	return &BrowserWebhook{config: config}
+}
+
+func (w *BrowserWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&browserv1.Browser{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *BrowserWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	browser, ok := obj.(*browserv1.Browser)
+	if !ok {
+		return fmt.Errorf("expected a Browser, got %T", obj)
+	}
+	if !w.config.Watches(browser.Namespace) {
+		return nil
+	}
+	if needsBrowserLabels(browser) {
+		setBrowserLabels(browser)
+	}
+	return nil
+}
+
+func (w *BrowserWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	browser, ok := obj.(*browserv1.Browser)
+	if !ok {
+		return nil, fmt.Errorf("expected a Browser, got %T", obj)
+	}
+	if !w.config.Watches(browser.Namespace) {
+		return nil, nil
+	}
+
+	errs := w.validateConfig(browser)
+	errs = append(errs, w.validatePriority(browser)...)
+	errs = append(errs, validateOptions(browser)...)
+	errs = append(errs, w.validatePolicies(browser)...)
+	return nil, invalidBrowser(browser, errs)
+}
+
+// ValidateUpdate only checks what changed, so Browsers whose config was removed after
+// creation can still have their finalizer and status updated.
+func (w *BrowserWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*browserv1.Browser)
+	if !ok {
+		return nil, fmt.Errorf("expected a Browser, got %T", oldObj)
+	}
+	browser, ok := newObj.(*browserv1.Browser)
+	if !ok {
+		return nil, fmt.Errorf("expected a Browser, got %T", newObj)
+	}
+	if !browser.DeletionTimestamp.IsZero() || !w.config.Watches(browser.Namespace) {
+		return nil, nil
+	}
+
+	specChanged := !equality.Semantic.DeepEqual(old.Spec, browser.Spec)
+	optionsChanged := old.Annotations[browserv1.SelenosisOptionsAnnotationKey] != browser.Annotations[browserv1.SelenosisOptionsAnnotationKey]
+
+	var errs field.ErrorList
+	if specChanged {
+		errs = append(errs, w.validateConfig(browser)...)
+		errs = append(errs, w.validatePriority(browser)...)
+	}
+	if optionsChanged {
+		errs = append(errs, validateOptions(browser)...)
+	}
+	if specChanged || optionsChanged {
+		errs = append(errs, w.validatePolicies(browser)...)
+	}
+	return nil, invalidBrowser(browser, errs)
+}
+
+func (w *BrowserWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateConfig rejects Browsers the BrowserConfigStore has no entry for.
+func (w *BrowserWebhook) validateConfig(browser *browserv1.Browser) field.ErrorList {
+	if _, _, ok := w.config.Resolve(browser.Namespace, browser.Spec.BrowserName, browser.Spec.BrowserVersion); ok {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "browserVersion"), browser.Spec.BrowserVersion,
+		fmt.Sprintf("no BrowserConfig entry found for %s:%s", browser.Spec.BrowserName, browser.Spec.BrowserVersion))}
+}
+
+// validatePriority rejects a spec.priority above the maxPriority of the namespace quota.
+// Without a quota Browsers are never queued and the priority has no effect.
+func (w *BrowserWebhook) validatePriority(browser *browserv1.Browser) field.ErrorList {
+	quota := w.config.Quota(browser.Namespace)
+	if quota == nil || browser.Spec.Priority == nil || *browser.Spec.Priority <= maxPriority(quota) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "priority"), *browser.Spec.Priority,
+		fmt.Sprintf("must be no greater than the quota maxPriority %d", maxPriority(quota)))}
+}
+
+// validateOptions rejects a selenosis options annotation the reconciler could not parse.
+func validateOptions(browser *browserv1.Browser) field.ErrorList {
+	if _, err := parseSelenosisOptions(browser.Annotations); err != nil {
+		path := field.NewPath("metadata", "annotations").Key(browserv1.SelenosisOptionsAnnotationKey)
+		return field.ErrorList{field.Invalid(path, browser.Annotations[browserv1.SelenosisOptionsAnnotationKey], err.Error())}
+	}
+	return nil
+}
+
+// validatePolicies rejects selenosis options the BrowserConfig optionsPolicy or resourcePolicy refuses.
+// Resource values the policy clamps are admitted, the reconciler records them in status.
+func (w *BrowserWebhook) validatePolicies(browser *browserv1.Browser) field.ErrorList {
+	cfg, _, ok := w.config.Resolve(browser.Namespace, browser.Spec.BrowserName, browser.Spec.BrowserVersion)
+	if !ok {
+		return nil
+	}
+	opts, err := parseSelenosisOptions(browser.Annotations)
+	if err != nil {
+		return nil
+	}
+	path := field.NewPath("metadata", "annotations").Key(browserv1.SelenosisOptionsAnnotationKey)
+	if err := optionsDenied(optionsViolations(opts, cfg.OptionsPolicy)); err != nil {
+		return field.ErrorList{field.Forbidden(path, err.Error())}
+	}
+	if _, err := boundResources(opts, cfg.ResourcePolicy); err != nil {
+		return field.ErrorList{field.Invalid(path, browser.Annotations[browserv1.SelenosisOptionsAnnotationKey], err.Error())}
+	}
+	return nil
+}
+
+func invalidBrowser(browser *browserv1.Browser, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(browserv1.SchemeGroupVersion.WithKind("Browser").GroupKind(), browser.Name, errs)
+}
+
+// needsBrowserLabels reports whether the selenosis.io/browser labels are missing or stale.
+func needsBrowserLabels(browser *browserv1.Browser) bool {
+	return browser.Labels[browserv1.SelenosisBrowserLabelKey] != browser.Name ||
+		browser.Labels[browserv1.SelenosisBrowserNameLabelKey] != browser.Spec.BrowserName ||
+		browser.Labels[browserv1.SelenosisBrowserVersionLabelKey] != versionLabelValue(browser)
+}
+
+// setBrowserLabels sets the selenosis.io/browser labels used to select Browsers and their pods.
+// The name label is left to the reconciler while a generateName Browser has no name yet.
+func setBrowserLabels(browser *browserv1.Browser) {
+	if browser.Labels == nil {
+		browser.Labels = map[string]string{}
+	}
+	if browser.Name != "" {
+		browser.Labels[browserv1.SelenosisBrowserLabelKey] = browser.Name
+	}
+	browser.Labels[browserv1.SelenosisBrowserNameLabelKey] = browser.Spec.BrowserName
+	browser.Labels[browserv1.SelenosisBrowserVersionLabelKey] = versionLabelValue(browser)
+}
+
+// versionLabelValue returns the selenosis.io/browser.version label of a Browser, its resolved version once
+// known. Version queries such as ">=118 <121" or "120.*" are not valid label values and are sanitized.
+func versionLabelValue(browser *browserv1.Browser) string {
+	return labelValue(resolvedVersion(browser))
+}
+
+// labelValue returns value when it is a valid label value, otherwise value with invalid characters
+// replaced by '-', or a hash of it when nothing valid is left.
+func labelValue(value string) string {
+	if len(validation.IsValidLabelValue(value)) == 0 {
+		return value
+	}
+
+	sanitized := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, value)
+	if len(sanitized) > validation.LabelValueMaxLength {
+		sanitized = sanitized[:validation.LabelValueMaxLength]
+	}
+	sanitized = strings.Trim(sanitized, "-_.")
+	if sanitized == "" {
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(value)))[:16]
+	}
+	return sanitized
+}
@@ -0,0 +1,161 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/store"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func webhookBrowser(version, options string) *browserv1.Browser {
+	b := &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Namespace: "ns"},
+		Spec:       browserv1.BrowserSpec{BrowserName: "chrome", BrowserVersion: version},
+	}
+	if options != "" {
+		b.Annotations = map[string]string{browserv1.SelenosisOptionsAnnotationKey: options}
+	}
+	return b
+}
+
+func TestBrowserWebhookDefaultsLabels(t *testing.T) {
+	w := NewBrowserWebhook(store.NewBrowserConfigStore())
+
+	b := webhookBrowser("120", "")
+	if err := w.Default(context.Background(), b); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if b.Labels[browserv1.SelenosisBrowserLabelKey] != "b1" ||
+		b.Labels[browserv1.SelenosisBrowserNameLabelKey] != "chrome" ||
+		b.Labels[browserv1.SelenosisBrowserVersionLabelKey] != "120" {
+		t.Fatalf("expected browser labels to be defaulted, got %v", b.Labels)
+	}
+
+	generated := webhookBrowser("120", "")
+	generated.Name = ""
+	generated.GenerateName = "b-"
+	if err := w.Default(context.Background(), generated); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := generated.Labels[browserv1.SelenosisBrowserLabelKey]; ok {
+		t.Fatalf("expected name label to be left to the reconciler")
+	}
+}
+
+func TestBrowserVersionLabelOfVersionQueries(t *testing.T) {
+	w := NewBrowserWebhook(store.NewBrowserConfigStore())
+
+	for _, query := range []string{">=118 <121", "120.*", "~120", "*", strings.Repeat("1.", 40)} {
+		b := webhookBrowser(query, "")
+		if err := w.Default(context.Background(), b); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		value := b.Labels[browserv1.SelenosisBrowserVersionLabelKey]
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			t.Fatalf("expected valid label value for %q, got %q: %v", query, value, errs)
+		}
+		if needsBrowserLabels(b) {
+			t.Fatalf("expected labels of %q to be stable once set", query)
+		}
+	}
+
+	b := webhookBrowser(">=118 <121", "")
+	b.Status.ResolvedVersion = "120.0"
+	setBrowserLabels(b)
+	if got := b.Labels[browserv1.SelenosisBrowserVersionLabelKey]; got != "120.0" {
+		t.Fatalf("expected resolved version label, got %q", got)
+	}
+
+	// a Browser labeled before its version was resolved runs a pod labeled with the resolved version
+	b = webhookBrowser(">=118 <121", "")
+	setBrowserLabels(b)
+	b.Status.ResolvedVersion = "120.0"
+	pod := buildBrowserPod(b, &configv1.BrowserVersionConfigSpec{Image: "img"}, nil)
+	if got := pod.Labels[browserv1.SelenosisBrowserVersionLabelKey]; got != "120.0" {
+		t.Fatalf("expected pod labeled with the resolved version, got %q", got)
+	}
+}
+
+func TestBrowserWebhookValidateCreate(t *testing.T) {
+	cfgStore := store.NewBrowserConfigStore()
+	setStoreConfig(t, cfgStore, "ns/chrome:120", &configv1.BrowserVersionConfigSpec{Image: "chrome:120"})
+	w := NewBrowserWebhook(cfgStore)
+
+	if _, err := w.ValidateCreate(context.Background(), webhookBrowser("120", `{"labels":{"team":"qa"}}`)); err != nil {
+		t.Fatalf("expected valid Browser to be accepted, got %v", err)
+	}
+
+	_, err := w.ValidateCreate(context.Background(), webhookBrowser("121", `{"labels":`))
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+	for _, want := range []string{
+		"spec.browserVersion: Invalid value: \"121\": no BrowserConfig entry found for chrome:121",
+		"metadata.annotations[selenosis.io/options]: Invalid value",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %q", want, err.Error())
+		}
+	}
+}
+
+func TestBrowserWebhookValidatesPriority(t *testing.T) {
+	w := NewBrowserWebhook(quotaStore(&configv1.BrowserQuota{MaxBrowsers: int32Ptr(5), MaxPriority: int32Ptr(10)}))
+
+	b := webhookBrowser("120", "")
+	b.Spec.Priority = int32Ptr(10)
+	if _, err := w.ValidateCreate(context.Background(), b); err != nil {
+		t.Fatalf("expected priority within the quota to be accepted, got %v", err)
+	}
+
+	b.Spec.Priority = int32Ptr(11)
+	_, err := w.ValidateCreate(context.Background(), b)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+	if want := "spec.priority: Invalid value: 11: must be no greater than the quota maxPriority 10"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in %q", want, err.Error())
+	}
+
+	w = NewBrowserWebhook(quotaStore(nil))
+	if _, err := w.ValidateCreate(context.Background(), b); err != nil {
+		t.Fatalf("expected priority to be ignored without a quota, got %v", err)
+	}
+}
+
+func TestBrowserWebhookValidateUpdateOnlyChecksChanges(t *testing.T) {
+	w := NewBrowserWebhook(store.NewBrowserConfigStore())
+
+	old := webhookBrowser("120", "")
+	updated := old.DeepCopy()
+	updated.Finalizers = []string{browserPodFinalizer}
+	if _, err := w.ValidateUpdate(context.Background(), old, updated); err != nil {
+		t.Fatalf("expected metadata update to be allowed without a config, got %v", err)
+	}
+
+	updated.Annotations = map[string]string{browserv1.SelenosisOptionsAnnotationKey: "not-json"}
+	if _, err := w.ValidateUpdate(context.Background(), old, updated); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected changed options to be validated, got %v", err)
+	}
+}
+
+func TestBrowserWebhookSkipsUnwatchedNamespaces(t *testing.T) {
+	w := NewBrowserWebhook(store.NewBrowserConfigStore().WithNamespaces("other"))
+
+	b := webhookBrowser("120", `{"labels":`)
+	if err := w.Default(context.Background(), b); err != nil || len(b.Labels) != 0 {
+		t.Fatalf("expected Browser of unwatched namespace to be left untouched, got %v %v", b.Labels, err)
+	}
+	if _, err := w.ValidateCreate(context.Background(), b); err != nil {
+		t.Fatalf("expected Browser of unwatched namespace to be admitted, got %v", err)
+	}
+	if _, err := w.ValidateUpdate(context.Background(), webhookBrowser("120", ""), b); err != nil {
+		t.Fatalf("expected update of unwatched namespace to be admitted, got %v", err)
+	}
+}
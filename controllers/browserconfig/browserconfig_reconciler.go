@@ -5,12 +5,15 @@ import (
 	"time"
 
 	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logger "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -18,23 +21,38 @@ const (
 	browserConfigFinalizer string = "browserconfig.selenosis.io/finalizer"
 	shortRetry                    = time.Second * 5
 	mediumRetry                   = time.Second * 10
+
+	// Event reasons recorded on BrowserConfig resources
+	eventReasonRegistered   = "Registered"
+	eventReasonUnregistered = "Unregistered"
+	eventReasonUpdateFailed = "UpdateFailed"
+	eventReasonInvalid      = "InvalidConfig"
+	eventReasonConflict     = "VersionConflict"
 )
 
+// +kubebuilder:rbac:groups=selenosis.io,resources=browserconfigs,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=selenosis.io,resources=browserconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=selenosis.io,resources=browserconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 type BrowserConfigReconciler struct {
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 
-func NewBrowserConfigReconciler(client client.Client, scheme *runtime.Scheme) *BrowserConfigReconciler {
+func NewBrowserConfigReconciler(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *BrowserConfigReconciler {
 	return &BrowserConfigReconciler{
-		client: client,
-		scheme: scheme,
+		client:   client,
+		scheme:   scheme,
+		recorder: recorder,
 	}
 }
 
 func (r *BrowserConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&configv1.BrowserConfig{}).
+		Watches(&configv1.BrowserConfig{}, handler.EnqueueRequestsFromMapFunc(r.namespaceConfigs)).
 		Complete(r)
 }
 
@@ -55,8 +73,10 @@ func (r BrowserConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			controllerutil.RemoveFinalizer(browserConfig, browserConfigFinalizer)
 			if err := r.client.Update(ctx, browserConfig); err != nil {
 				log.Error(err, "failed to remove finalizer")
+				r.recorder.Eventf(browserConfig, corev1.EventTypeWarning, eventReasonUpdateFailed, "Failed to remove finalizer: %v", err)
 				return ctrl.Result{RequeueAfter: shortRetry}, err
 			}
+			r.recorder.Event(browserConfig, corev1.EventTypeNormal, eventReasonUnregistered, "BrowserConfig removed from the controller")
 		}
 		return ctrl.Result{}, nil
 	}
@@ -65,8 +85,16 @@ func (r BrowserConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		controllerutil.AddFinalizer(browserConfig, browserConfigFinalizer)
 		if err := r.client.Update(ctx, browserConfig); err != nil {
 			log.Error(err, "failed to add finalizer")
+			r.recorder.Eventf(browserConfig, corev1.EventTypeWarning, eventReasonUpdateFailed, "Failed to add finalizer: %v", err)
 			return ctrl.Result{RequeueAfter: shortRetry}, err
 		}
+		r.recorder.Eventf(browserConfig, corev1.EventTypeNormal, eventReasonRegistered,
+			"BrowserConfig registered with %d browser(s)", len(browserConfig.Spec.Browsers))
+	}
+
+	if err := r.updateStatus(ctx, browserConfig); err != nil {
+		log.Error(err, "failed to update BrowserConfig status")
+		return ctrl.Result{RequeueAfter: shortRetry}, err
 	}
 
 	return ctrl.Result{}, nil
@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -30,8 +31,8 @@ func newTestScheme(t *testing.T) *runtime.Scheme {
 
 func TestReconcileNotFound(t *testing.T) {
 	scheme := newTestScheme(t)
-	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
-	r := NewBrowserConfigReconciler(cl, scheme)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).Build()
+	r := NewBrowserConfigReconciler(cl, scheme, record.NewFakeRecorder(10))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "default", Name: "missing"},
@@ -54,8 +55,8 @@ func TestReconcileAddsFinalizer(t *testing.T) {
 			},
 		},
 	}
-	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cfg).Build()
-	r := NewBrowserConfigReconciler(cl, scheme)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(cfg).Build()
+	r := NewBrowserConfigReconciler(cl, scheme, record.NewFakeRecorder(10))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "default", Name: "cfg"},
@@ -89,8 +90,8 @@ func TestReconcileRemovesFinalizerOnDelete(t *testing.T) {
 			},
 		},
 	}
-	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cfg).Build()
-	r := NewBrowserConfigReconciler(cl, scheme)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(cfg).Build()
+	r := NewBrowserConfigReconciler(cl, scheme, record.NewFakeRecorder(10))
 
 	_, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "default", Name: "cfg"},
@@ -129,8 +130,8 @@ func (e errorClient) Update(ctx context.Context, obj client.Object, opts ...clie
 
 func TestReconcileGetError(t *testing.T) {
 	scheme := newTestScheme(t)
-	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
-	r := NewBrowserConfigReconciler(errorClient{Client: cl, getErr: errors.New("boom")}, scheme)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).Build()
+	r := NewBrowserConfigReconciler(errorClient{Client: cl, getErr: errors.New("boom")}, scheme, record.NewFakeRecorder(10))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "default", Name: "cfg"},
@@ -156,8 +157,8 @@ func TestReconcileAddFinalizerUpdateError(t *testing.T) {
 			},
 		},
 	}
-	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cfg).Build()
-	r := NewBrowserConfigReconciler(errorClient{Client: base, updateErr: errors.New("update")}, scheme)
+	base := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(cfg).Build()
+	r := NewBrowserConfigReconciler(errorClient{Client: base, updateErr: errors.New("update")}, scheme, record.NewFakeRecorder(10))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "default", Name: "cfg"},
@@ -186,8 +187,8 @@ func TestReconcileRemoveFinalizerUpdateError(t *testing.T) {
 			},
 		},
 	}
-	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cfg).Build()
-	r := NewBrowserConfigReconciler(errorClient{Client: base, updateErr: errors.New("update")}, scheme)
+	base := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(cfg).Build()
+	r := NewBrowserConfigReconciler(errorClient{Client: base, updateErr: errors.New("update")}, scheme, record.NewFakeRecorder(10))
 
 	res, err := r.Reconcile(context.Background(), ctrl.Request{
 		NamespacedName: client.ObjectKey{Namespace: "default", Name: "cfg"},
@@ -199,3 +200,33 @@ func TestReconcileRemoveFinalizerUpdateError(t *testing.T) {
 		t.Fatalf("expected short retry, got %v", res.RequeueAfter)
 	}
 }
+
+func TestReconcileRecordsRegisteredEvent(t *testing.T) {
+	scheme := newTestScheme(t)
+	cfg := &configv1.BrowserConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "default"},
+		Spec: configv1.BrowserConfigSpec{
+			Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{
+				"chrome": {"120": {Image: "img"}},
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(cfg).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserConfigReconciler(cl, scheme, recorder)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "default", Name: "cfg"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if event != "Normal Registered BrowserConfig registered with 1 browser(s)" {
+			t.Fatalf("unexpected event %q", event)
+		}
+	default:
+		t.Fatalf("expected Registered event")
+	}
+}
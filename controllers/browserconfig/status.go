@@ -0,0 +1,186 @@
+package browserconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// updateStatus publishes the content hash, browser inventory and validation result of the merged spec.
+func (r *BrowserConfigReconciler) updateStatus(ctx context.Context, bc *configv1.BrowserConfig) error {
+	merged := bc.Spec.DeepCopy()
+	merged.MergeWithTemplate()
+
+	hash, err := specHash(merged)
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := r.conflicts(ctx, bc)
+	if err != nil {
+		return err
+	}
+
+	invalid := validateSpec(merged)
+
+	before := bc.DeepCopy()
+	status := &bc.Status
+
+	if status.Version != hash {
+		status.Version = hash
+		status.LastUpdated = metav1.Now()
+	}
+	status.ObservedGeneration = bc.Generation
+	status.Browsers = inventory(merged)
+
+	if len(invalid) == 0 {
+		setCondition(bc, configv1.BrowserConfigValid, metav1.ConditionTrue, configv1.ReasonSpecValid, "")
+	} else {
+		setCondition(bc, configv1.BrowserConfigValid, metav1.ConditionFalse, configv1.ReasonSpecInvalid, invalid.ToAggregate().Error())
+	}
+
+	if len(conflicts) == 0 {
+		setCondition(bc, configv1.BrowserConfigConflicting, metav1.ConditionFalse, configv1.ReasonNoConflicts, "")
+	} else {
+		setCondition(bc, configv1.BrowserConfigConflicting, metav1.ConditionTrue, configv1.ReasonVersionConflict, strings.Join(conflicts, "; "))
+	}
+
+	if equality.Semantic.DeepEqual(before.Status, bc.Status) {
+		return nil
+	}
+
+	if err := r.client.Status().Patch(ctx, bc, client.MergeFrom(before)); err != nil {
+		return err
+	}
+
+	if len(invalid) > 0 && !meta.IsStatusConditionFalse(before.Status.Conditions, configv1.BrowserConfigValid) {
+		r.recorder.Eventf(bc, corev1.EventTypeWarning, eventReasonInvalid, "BrowserConfig is invalid: %s", invalid.ToAggregate().Error())
+	}
+	if len(conflicts) > 0 && !meta.IsStatusConditionTrue(before.Status.Conditions, configv1.BrowserConfigConflicting) {
+		r.recorder.Eventf(bc, corev1.EventTypeWarning, eventReasonConflict, "BrowserConfig conflicts: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// conflicts lists browser versions also defined by other BrowserConfigs in the namespace.
+// The oldest BrowserConfig wins, ties are broken by name, matching the BrowserConfigStore.
+func (r *BrowserConfigReconciler) conflicts(ctx context.Context, bc *configv1.BrowserConfig) ([]string, error) {
+	list := &configv1.BrowserConfigList{}
+	if err := r.client.List(ctx, list, client.InNamespace(bc.Namespace)); err != nil {
+		return nil, fmt.Errorf("list BrowserConfigs: %w", err)
+	}
+
+	own := versionKeys(&bc.Spec)
+	var conflicts []string
+
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.Name == bc.Name || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		winner := bc.Name
+		if precedes(other, bc) {
+			winner = other.Name
+		}
+
+		for _, key := range versionKeys(&other.Spec) {
+			if !containsString(own, key) {
+				continue
+			}
+			conflicts = append(conflicts, fmt.Sprintf("%s also defined by %s, served from %s", key, other.Name, winner))
+		}
+	}
+
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+// namespaceConfigs enqueues the other BrowserConfigs of a namespace, their conflicts may change with obj.
+func (r *BrowserConfigReconciler) namespaceConfigs(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &configv1.BrowserConfigList{}
+	if err := r.client.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].Name == obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return requests
+}
+
+func precedes(a, b *configv1.BrowserConfig) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// versionKeys returns the sorted, lowercased browser:version keys of a spec.
+func versionKeys(spec *configv1.BrowserConfigSpec) []string {
+	var keys []string
+	for browserName, versions := range spec.Browsers {
+		for version := range versions {
+			keys = append(keys, strings.ToLower(browserName)+":"+strings.ToLower(version))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(sorted []string, s string) bool {
+	i := sort.SearchStrings(sorted, s)
+	return i < len(sorted) && sorted[i] == s
+}
+
+// inventory lists the browser versions of a merged spec with their images.
+func inventory(spec *configv1.BrowserConfigSpec) []configv1.BrowserVersionStatus {
+	var browsers []configv1.BrowserVersionStatus
+	for _, browserName := range sortedKeys(spec.Browsers) {
+		versions := spec.Browsers[browserName]
+		for _, version := range sortedKeys(versions) {
+			status := configv1.BrowserVersionStatus{Name: browserName, Version: version}
+			if cfg := versions[version]; cfg != nil {
+				status.Image = cfg.Image
+			}
+			browsers = append(browsers, status)
+		}
+	}
+	return browsers
+}
+
+// specHash returns a short content hash of the merged spec.
+func specHash(spec *configv1.BrowserConfigSpec) (string, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("marshal BrowserConfig spec: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+func setCondition(bc *configv1.BrowserConfig, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&bc.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: bc.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
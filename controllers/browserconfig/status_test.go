@@ -0,0 +1,160 @@
+package browserconfig
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func validConfig(name string, created time.Time, versions ...string) *configv1.BrowserConfig {
+	chrome := map[string]*configv1.BrowserVersionConfigSpec{}
+	for _, v := range versions {
+		chrome[v] = &configv1.BrowserVersionConfigSpec{Image: "chrome:" + v}
+	}
+	return &configv1.BrowserConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+		Spec: configv1.BrowserConfigSpec{
+			Template: &configv1.Template{
+				Sidecars: &[]configv1.Sidecar{{Name: seleniferousSidecarName, Image: "seleniferous"}},
+			},
+			Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{"chrome": chrome},
+		},
+	}
+}
+
+func reconcileConfig(t *testing.T, r *BrowserConfigReconciler, cl client.Client, name string) *configv1.BrowserConfig {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "default", Name: name},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got := &configv1.BrowserConfig{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, got); err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+	return got
+}
+
+func TestValidateSpec(t *testing.T) {
+	spec := &configv1.BrowserConfigSpec{
+		Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{
+			"chrome": {
+				"120.0": {
+					Sidecars: &[]configv1.Sidecar{
+						{Name: "proxy", Image: "proxy"},
+						{Name: "proxy", Image: "proxy"},
+						{Name: browserContainerName, Image: "x"},
+					},
+				},
+				"121.0": nil,
+			},
+		},
+	}
+
+	errs := validateSpec(spec)
+	got := errs.ToAggregate().Error()
+	for _, want := range []string{
+		"spec.browsers[chrome][120.0].image: Required value",
+		"spec.browsers[chrome][120.0].sidecars[1].name: Duplicate value: \"proxy\"",
+		"spec.browsers[chrome][120.0].sidecars[2].name: Invalid value: \"browser\"",
+		"spec.browsers[chrome][120.0].sidecars: Required value: a seleniferous sidecar or a critical proxy sidecar is required",
+		"spec.browsers[chrome][121.0]: Required value",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in %q", want, got)
+		}
+	}
+
+	valid := validConfig("cfg", time.Now(), "120.0")
+	valid.Spec.MergeWithTemplate()
+	if errs := validateSpec(&valid.Spec); len(errs) != 0 {
+		t.Fatalf("expected valid spec, got %v", errs)
+	}
+}
+
+func TestReconcilePopulatesStatus(t *testing.T) {
+	scheme := newTestScheme(t)
+	cfg := validConfig("cfg", time.Now(), "121.0", "120.0")
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(cfg).Build()
+	r := NewBrowserConfigReconciler(cl, scheme, record.NewFakeRecorder(10))
+
+	got := reconcileConfig(t, r, cl, "cfg")
+	if len(got.Status.Version) != 16 || got.Status.LastUpdated.IsZero() {
+		t.Fatalf("expected version hash and lastUpdated, got %+v", got.Status)
+	}
+	if len(got.Status.Browsers) != 2 ||
+		got.Status.Browsers[0] != (configv1.BrowserVersionStatus{Name: "chrome", Version: "120.0", Image: "chrome:120.0"}) {
+		t.Fatalf("unexpected browsers: %+v", got.Status.Browsers)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, configv1.BrowserConfigValid) {
+		t.Fatalf("expected Valid condition")
+	}
+	if !meta.IsStatusConditionFalse(got.Status.Conditions, configv1.BrowserConfigConflicting) {
+		t.Fatalf("expected Conflicting=False")
+	}
+
+	// the hash is stable across reconciles
+	version := got.Status.Version
+	if again := reconcileConfig(t, r, cl, "cfg"); again.Status.Version != version {
+		t.Fatalf("expected stable version, got %q and %q", version, again.Status.Version)
+	}
+}
+
+func TestReconcileReportsInvalidSpec(t *testing.T) {
+	scheme := newTestScheme(t)
+	cfg := validConfig("cfg", time.Now(), "120.0")
+	cfg.Spec.Template = nil
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(cfg).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := NewBrowserConfigReconciler(cl, scheme, recorder)
+
+	got := reconcileConfig(t, r, cl, "cfg")
+	valid := meta.FindStatusCondition(got.Status.Conditions, configv1.BrowserConfigValid)
+	if valid == nil || valid.Status != metav1.ConditionFalse || valid.Reason != configv1.ReasonSpecInvalid ||
+		!strings.Contains(valid.Message, "seleniferous") {
+		t.Fatalf("unexpected Valid condition: %+v", valid)
+	}
+
+	<-recorder.Events // Registered
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, "Warning InvalidConfig") {
+			t.Fatalf("unexpected event %q", event)
+		}
+	default:
+		t.Fatalf("expected InvalidConfig event")
+	}
+}
+
+func TestReconcileReportsConflicts(t *testing.T) {
+	scheme := newTestScheme(t)
+	older := validConfig("older", time.Now().Add(-time.Hour), "120.0")
+	newer := validConfig("newer", time.Now(), "120.0", "121.0")
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&configv1.BrowserConfig{}).WithObjects(older, newer).Build()
+	r := NewBrowserConfigReconciler(cl, scheme, record.NewFakeRecorder(10))
+
+	for _, name := range []string{"older", "newer"} {
+		got := reconcileConfig(t, r, cl, name)
+		conflicting := meta.FindStatusCondition(got.Status.Conditions, configv1.BrowserConfigConflicting)
+		if conflicting == nil || conflicting.Status != metav1.ConditionTrue ||
+			!strings.Contains(conflicting.Message, "chrome:120.0") ||
+			!strings.Contains(conflicting.Message, "served from older") {
+			t.Fatalf("unexpected Conflicting condition on %s: %+v", name, conflicting)
+		}
+	}
+
+	requests := r.namespaceConfigs(context.Background(), newer)
+	if len(requests) != 1 || requests[0].Name != "older" {
+		t.Fatalf("expected other BrowserConfigs to be enqueued, got %v", requests)
+	}
+}
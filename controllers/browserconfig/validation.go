@@ -0,0 +1,294 @@
+package browserconfig
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	browserv1 "github.com/alcounit/browser-controller/apis/browser/v1"
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"github.com/alcounit/browser-controller/controllers/browser"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// browserContainerName is reserved for the main container rendered by the browser controller
+	browserContainerName = "browser"
+	// seleniferousSidecarName is the session proxy sidecar every browser pod needs
+	seleniferousSidecarName = "seleniferous"
+)
+
+// validateSpec checks a merged BrowserConfigSpec for problems that would break browser pods.
+func validateSpec(spec *configv1.BrowserConfigSpec) field.ErrorList {
+	var errs field.ErrorList
+
+	browsersPath := field.NewPath("spec", "browsers")
+	for _, browserName := range sortedKeys(spec.Browsers) {
+		versions := spec.Browsers[browserName]
+		for _, version := range sortedKeys(versions) {
+			path := browsersPath.Key(browserName).Key(version)
+
+			cfg := versions[version]
+			if cfg == nil {
+				errs = append(errs, field.Required(path, "browser version config must not be empty"))
+				continue
+			}
+
+			if cfg.Image == "" {
+				errs = append(errs, field.Required(path.Child("image"), "browser image must be set"))
+			}
+
+			errs = append(errs, validateContainers(path.Child("sidecars"), cfg.Sidecars)...)
+			errs = append(errs, validateContainers(path.Child("initContainers"), cfg.InitContainers)...)
+			errs = append(errs, validateInitContainers(path.Child("initContainers"), cfg.InitContainers)...)
+			errs = append(errs, validateSidecarRestartPolicy(path.Child("sidecars"), cfg.Sidecars)...)
+
+			if !hasSessionProxy(cfg) {
+				errs = append(errs, field.Required(path.Child("sidecars"), "a seleniferous sidecar or a critical proxy sidecar is required"))
+			}
+
+			errs = append(errs, validateNetworkPolicy(path.Child("networkPolicy"), cfg.NetworkPolicy)...)
+			errs = append(errs, validateResourcePolicy(path.Child("resourcePolicy"), cfg.ResourcePolicy)...)
+
+			pod, err := browser.RenderPod(sampleBrowser(browserName, version), cfg)
+			if err != nil {
+				errs = append(errs, field.InternalError(path, err))
+				continue
+			}
+			errs = append(errs, validatePod(path, pod)...)
+		}
+	}
+
+	return errs
+}
+
+// validateContainers rejects unnamed, duplicate and reserved container names.
+func validateContainers(path *field.Path, containers *[]configv1.Sidecar) field.ErrorList {
+	if containers == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	seen := map[string]struct{}{}
+	for i, c := range *containers {
+		namePath := path.Index(i).Child("name")
+		switch {
+		case c.Name == "":
+			errs = append(errs, field.Required(namePath, "container name must be set"))
+		case c.Name == browserContainerName:
+			errs = append(errs, field.Invalid(namePath, c.Name, "name is reserved for the browser container"))
+		default:
+			if _, ok := seen[c.Name]; ok {
+				errs = append(errs, field.Duplicate(namePath, c.Name))
+			}
+			seen[c.Name] = struct{}{}
+		}
+
+		if c.Image == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("image"), "container image must be set"))
+		}
+	}
+	return errs
+}
+
+// validateInitContainers rejects probes and lifecycle hooks on init containers that run to completion
+// before the browser starts, the API server only accepts them on native sidecars.
+func validateInitContainers(path *field.Path, containers *[]configv1.Sidecar) field.ErrorList {
+	if containers == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	for i, c := range *containers {
+		if c.IsNativeSidecar() {
+			continue
+		}
+		p := path.Index(i)
+		if c.Critical != nil && *c.Critical {
+			errs = append(errs, field.Forbidden(p.Child("critical"), "only supported for sidecars and native sidecars"))
+		}
+		if c.ReadinessProbe != nil {
+			errs = append(errs, field.Forbidden(p.Child("readinessProbe"), "not supported for init containers"))
+		}
+		if c.LivenessProbe != nil {
+			errs = append(errs, field.Forbidden(p.Child("livenessProbe"), "not supported for init containers"))
+		}
+		if c.StartupProbe != nil {
+			errs = append(errs, field.Forbidden(p.Child("startupProbe"), "not supported for init containers"))
+		}
+		if c.Lifecycle != nil {
+			errs = append(errs, field.Forbidden(p.Child("lifecycle"), "not supported for init containers"))
+		}
+	}
+	return errs
+}
+
+// validateSidecarRestartPolicy rejects restartPolicy on sidecars, native sidecars are declared as initContainers.
+func validateSidecarRestartPolicy(path *field.Path, containers *[]configv1.Sidecar) field.ErrorList {
+	if containers == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	for i, c := range *containers {
+		if c.RestartPolicy != nil {
+			errs = append(errs, field.Forbidden(path.Index(i).Child("restartPolicy"), "only supported for initContainers"))
+		}
+	}
+	return errs
+}
+
+// validateNetworkPolicy rejects policies restricting nothing and egress CIDRs the NetworkPolicy API would refuse.
+func validateNetworkPolicy(path *field.Path, np *configv1.BrowserNetworkPolicy) field.ErrorList {
+	if np == nil {
+		return nil
+	}
+	if np.IngressFrom == nil && np.Egress == nil {
+		return field.ErrorList{field.Required(path, "ingressFrom or egress must be set")}
+	}
+	if np.Egress == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	for i, rule := range *np.Egress {
+		for j, cidr := range rule.CIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				errs = append(errs, field.Invalid(path.Child("egress").Index(i).Child("cidrs").Index(j), cidr, "must be a valid CIDR"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateResourcePolicy rejects bounds where the minimum exceeds the maximum and allowed values outside the bounds.
+func validateResourcePolicy(path *field.Path, policy *configv1.ResourcePolicy) field.ErrorList {
+	if policy == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	for _, res := range sortedKeys(policy.Min) {
+		min := policy.Min[res]
+		if max, ok := policy.Max[res]; ok && min.Cmp(max) > 0 {
+			errs = append(errs, field.Invalid(path.Child("min").Key(string(res)), min.String(),
+				fmt.Sprintf("must not exceed max %s", max.String())))
+		}
+	}
+	for _, res := range sortedKeys(policy.Allowed) {
+		for i, value := range policy.Allowed[res] {
+			valuePath := path.Child("allowed").Key(string(res)).Index(i)
+			if min, ok := policy.Min[res]; ok && value.Cmp(min) < 0 {
+				errs = append(errs, field.Invalid(valuePath, value.String(), fmt.Sprintf("must not be below min %s", min.String())))
+			}
+			if max, ok := policy.Max[res]; ok && value.Cmp(max) > 0 {
+				errs = append(errs, field.Invalid(valuePath, value.String(), fmt.Sprintf("must not exceed max %s", max.String())))
+			}
+		}
+	}
+	return errs
+}
+
+// validatePod checks a rendered browser pod and reports problems against the config fields they came from.
+func validatePod(path *field.Path, pod *corev1.Pod) field.ErrorList {
+	var errs field.ErrorList
+
+	volumes := map[string]struct{}{}
+	for i, v := range pod.Spec.Volumes {
+		if _, ok := volumes[v.Name]; ok {
+			errs = append(errs, field.Duplicate(path.Child("volumes").Index(i).Child("name"), v.Name))
+		}
+		volumes[v.Name] = struct{}{}
+	}
+
+	spreads := map[string]struct{}{}
+	for i, c := range pod.Spec.TopologySpreadConstraints {
+		key := c.TopologyKey + "/" + string(c.WhenUnsatisfiable)
+		if _, ok := spreads[key]; ok {
+			errs = append(errs, field.Duplicate(path.Child("topologySpreadConstraints").Index(i),
+				fmt.Sprintf("{%s, %s}", c.TopologyKey, c.WhenUnsatisfiable)))
+		}
+		spreads[key] = struct{}{}
+	}
+
+	containers := map[string]struct{}{}
+	for i, c := range pod.Spec.Containers {
+		containers[c.Name] = struct{}{}
+		errs = append(errs, validateVolumeMounts(containerPath(path, i).Child("volumeMounts"), c.VolumeMounts, volumes)...)
+	}
+
+	for i, c := range pod.Spec.InitContainers {
+		initPath := path.Child("initContainers").Index(i)
+		if _, ok := containers[c.Name]; ok && c.Name != "" && c.Name != browserContainerName {
+			errs = append(errs, field.Duplicate(initPath.Child("name"), c.Name))
+		}
+		errs = append(errs, validateVolumeMounts(initPath.Child("volumeMounts"), c.VolumeMounts, volumes)...)
+	}
+
+	return errs
+}
+
+// validateVolumeMounts rejects mounts of volumes the pod does not declare.
+func validateVolumeMounts(path *field.Path, mounts []corev1.VolumeMount, volumes map[string]struct{}) field.ErrorList {
+	var errs field.ErrorList
+	for i, m := range mounts {
+		if _, ok := volumes[m.Name]; !ok {
+			errs = append(errs, field.NotFound(path.Index(i).Child("name"), m.Name))
+		}
+	}
+	return errs
+}
+
+// containerPath maps a rendered pod container back to its config field,
+// the browser container comes first and is followed by the sidecars.
+func containerPath(path *field.Path, i int) *field.Path {
+	if i == 0 {
+		return path
+	}
+	return path.Child("sidecars").Index(i - 1)
+}
+
+// sampleBrowser is the Browser a config is rendered for during validation.
+func sampleBrowser(browserName, version string) *browserv1.Browser {
+	return &browserv1.Browser{
+		ObjectMeta: metav1.ObjectMeta{Name: "validation"},
+		Spec: browserv1.BrowserSpec{
+			BrowserName:    browserName,
+			BrowserVersion: version,
+		},
+	}
+}
+
+// hasSessionProxy reports whether the pod gets a session proxy: the seleniferous sidecar
+// or a differently named sidecar marked critical, either as container or native sidecar.
+func hasSessionProxy(cfg *configv1.BrowserVersionConfigSpec) bool {
+	isProxy := func(c configv1.Sidecar) bool {
+		return c.Name == seleniferousSidecarName || (c.Critical != nil && *c.Critical)
+	}
+	if cfg.Sidecars != nil {
+		for _, c := range *cfg.Sidecars {
+			if isProxy(c) {
+				return true
+			}
+		}
+	}
+	if cfg.InitContainers != nil {
+		for _, c := range *cfg.InitContainers {
+			if c.IsNativeSidecar() && isProxy(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
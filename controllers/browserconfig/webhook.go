@@ -0,0 +1,68 @@
+package browserconfig
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-selenosis-io-v1-browserconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=selenosis.io,resources=browserconfigs,verbs=create;update,versions=v1,name=vbrowserconfig.selenosis.io,admissionReviewVersions=v1
+
+// BrowserConfigValidator rejects BrowserConfigs that cannot be rendered into browser pods.
+type BrowserConfigValidator struct{}
+
+var _ admission.CustomValidator = &BrowserConfigValidator{}
+
+func (v *BrowserConfigValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&configv1.BrowserConfig{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *BrowserConfigValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	bc, ok := obj.(*configv1.BrowserConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a BrowserConfig, got %T", obj)
+	}
+	return nil, validateBrowserConfig(bc)
+}
+
+// ValidateUpdate only validates spec changes, metadata updates such as finalizer
+// removal must keep working for configs created before the webhook was enabled.
+func (v *BrowserConfigValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldBC, ok := oldObj.(*configv1.BrowserConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a BrowserConfig, got %T", oldObj)
+	}
+	bc, ok := newObj.(*configv1.BrowserConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected a BrowserConfig, got %T", newObj)
+	}
+
+	if !bc.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldBC.Spec, bc.Spec) {
+		return nil, nil
+	}
+	return nil, validateBrowserConfig(bc)
+}
+
+func (v *BrowserConfigValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateBrowserConfig merges the template into every browser version and validates the result.
+func validateBrowserConfig(bc *configv1.BrowserConfig) error {
+	merged := bc.Spec.DeepCopy()
+	merged.MergeWithTemplate()
+
+	if errs := validateSpec(merged); len(errs) > 0 {
+		return apierrors.NewInvalid(configv1.SchemeGroupVersion.WithKind("BrowserConfig").GroupKind(), bc.Name, errs)
+	}
+	return nil
+}
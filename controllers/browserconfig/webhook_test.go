@@ -0,0 +1,202 @@
+package browserconfig
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestBrowserConfigValidatorAcceptsValidConfig(t *testing.T) {
+	v := &BrowserConfigValidator{}
+	if _, err := v.ValidateCreate(context.Background(), validConfig("cfg", time.Now(), "120.0")); err != nil {
+		t.Fatalf("expected valid config to be accepted, got %v", err)
+	}
+}
+
+func TestBrowserConfigValidatorRejectsRenderedPodErrors(t *testing.T) {
+	bc := validConfig("cfg", time.Now(), "120.0")
+	bc.Spec.Template.Volumes = &[]corev1.Volume{{Name: "dshm"}}
+	bc.Spec.Template.InitContainers = &[]configv1.Sidecar{{Name: seleniferousSidecarName, Image: "init"}}
+	bc.Spec.Template.Sidecars = &[]configv1.Sidecar{{
+		Name:         seleniferousSidecarName,
+		Image:        "seleniferous",
+		VolumeMounts: &[]corev1.VolumeMount{{Name: "missing", MountPath: "/missing"}},
+	}}
+	version := bc.Spec.Browsers["chrome"]["120.0"]
+	version.Image = ""
+	version.Volumes = &[]corev1.Volume{{Name: "dshm"}}
+	version.VolumeMounts = &[]corev1.VolumeMount{{Name: "dshm", MountPath: "/dev/shm"}}
+
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+
+	got := err.Error()
+	for _, want := range []string{
+		"spec.browsers[chrome][120.0].image: Required value",
+		"spec.browsers[chrome][120.0].volumes[1].name: Duplicate value: \"dshm\"",
+		"spec.browsers[chrome][120.0].sidecars[0].volumeMounts[0].name: Not found: \"missing\"",
+		"spec.browsers[chrome][120.0].initContainers[0].name: Duplicate value: \"seleniferous\"",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in %q", want, got)
+		}
+	}
+}
+
+func TestBrowserConfigValidatorRejectsInvalidEgressCIDR(t *testing.T) {
+	bc := validConfig("cfg", time.Now(), "120.0")
+	bc.Spec.Template.NetworkPolicy = &configv1.BrowserNetworkPolicy{
+		Egress: &[]configv1.BrowserEgressRule{{CIDRs: []string{"10.0.0.0/8", "10.0.0.1"}}},
+	}
+
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+	if want := "spec.browsers[chrome][120.0].networkPolicy.egress[0].cidrs[1]"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in %q", want, err.Error())
+	}
+}
+
+func TestBrowserConfigValidatorRejectsEmptyNetworkPolicy(t *testing.T) {
+	bc := validConfig("cfg", time.Now(), "120.0")
+	bc.Spec.Template.NetworkPolicy = &configv1.BrowserNetworkPolicy{Scope: configv1.NetworkPolicyScopeShared}
+
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+	if want := "spec.browsers[chrome][120.0].networkPolicy: Required value"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in %q", want, err.Error())
+	}
+}
+
+func TestBrowserConfigValidatorRejectsInitContainerProbes(t *testing.T) {
+	bc := validConfig("cfg", time.Now(), "120.0")
+	bc.Spec.Template.InitContainers = &[]configv1.Sidecar{{
+		Name:           "init",
+		Image:          "init",
+		ReadinessProbe: &corev1.Probe{},
+		Lifecycle:      &corev1.Lifecycle{},
+	}}
+
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+	for _, want := range []string{
+		"spec.browsers[chrome][120.0].initContainers[0].readinessProbe: Forbidden",
+		"spec.browsers[chrome][120.0].initContainers[0].lifecycle: Forbidden",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %q", want, err.Error())
+		}
+	}
+}
+
+func TestBrowserConfigValidatorNativeSidecars(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	bc := validConfig("cfg", time.Now(), "120.0")
+	seleniferous := (*bc.Spec.Template.Sidecars)[0]
+	seleniferous.RestartPolicy = &always
+	seleniferous.ReadinessProbe = &corev1.Probe{}
+	bc.Spec.Template.InitContainers = &[]configv1.Sidecar{seleniferous}
+	bc.Spec.Template.Sidecars = nil
+
+	if _, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc); err != nil {
+		t.Fatalf("expected seleniferous as native sidecar to be accepted, got %v", err)
+	}
+
+	bc = validConfig("cfg", time.Now(), "120.0")
+	(*bc.Spec.Template.Sidecars)[0].RestartPolicy = &always
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if want := "spec.browsers[chrome][120.0].sidecars[0].restartPolicy: Forbidden"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestBrowserConfigValidatorCriticalSidecars(t *testing.T) {
+	critical := true
+	bc := validConfig("cfg", time.Now(), "120.0")
+	bc.Spec.Template.Sidecars = &[]configv1.Sidecar{{Name: "proxy", Image: "proxy", Critical: &critical}}
+
+	if _, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc); err != nil {
+		t.Fatalf("expected critical proxy sidecar to replace seleniferous, got %v", err)
+	}
+
+	bc.Spec.Template.InitContainers = &[]configv1.Sidecar{{Name: "setup", Image: "setup", Critical: &critical}}
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if want := "spec.browsers[chrome][120.0].initContainers[0].critical: Forbidden"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestBrowserConfigValidatorRejectsInvertedResourceBounds(t *testing.T) {
+	bc := validConfig("cfg", time.Now(), "120.0")
+	bc.Spec.Template.ResourcePolicy = &configv1.ResourcePolicy{
+		Min: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+		Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if want := "spec.browsers[chrome][120.0].resourcePolicy.min[memory]: Invalid value: \"8Gi\": must not exceed max 4Gi"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestBrowserConfigValidatorRejectsAllowedValuesOutOfBounds(t *testing.T) {
+	bc := validConfig("cfg", time.Now(), "120.0")
+	bc.Spec.Template.ResourcePolicy = &configv1.ResourcePolicy{
+		Max:     corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+		Allowed: map[corev1.ResourceName][]resource.Quantity{corev1.ResourceMemory: {resource.MustParse("2Gi"), resource.MustParse("8Gi")}},
+	}
+
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if want := "spec.browsers[chrome][120.0].resourcePolicy.allowed[memory][1]: Invalid value: \"8Gi\": must not exceed max 4Gi"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestBrowserConfigValidatorAllowsMetadataUpdates(t *testing.T) {
+	old := validConfig("cfg", time.Now(), "120.0")
+	old.Spec.Browsers["chrome"]["120.0"].Image = ""
+
+	updated := old.DeepCopy()
+	updated.Finalizers = []string{browserConfigFinalizer}
+
+	v := &BrowserConfigValidator{}
+	if _, err := v.ValidateUpdate(context.Background(), old, updated); err != nil {
+		t.Fatalf("expected metadata update to be allowed, got %v", err)
+	}
+
+	updated.Spec.Browsers["chrome"]["121.0"] = &configv1.BrowserVersionConfigSpec{Image: "chrome:121.0"}
+	if _, err := v.ValidateUpdate(context.Background(), old, updated); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected spec update to be validated, got %v", err)
+	}
+}
+
+func TestBrowserConfigValidatorRejectsDuplicateTopologySpreadConstraints(t *testing.T) {
+	bc := validConfig("cfg", time.Now(), "120.0")
+	zone := corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule}
+	bc.Spec.Template.TopologySpreadConstraints = &[]corev1.TopologySpreadConstraint{zone}
+	bc.Spec.Browsers["chrome"]["120.0"].TopologySpreadConstraints = &[]corev1.TopologySpreadConstraint{zone, zone}
+
+	_, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc)
+	if want := "spec.browsers[chrome][120.0].topologySpreadConstraints[1]: Duplicate value"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+
+	// a version overriding the template constraint is not a duplicate
+	bc.Spec.Browsers["chrome"]["120.0"].TopologySpreadConstraints = &[]corev1.TopologySpreadConstraint{zone}
+	if _, err := (&BrowserConfigValidator{}).ValidateCreate(context.Background(), bc); err != nil {
+		t.Fatalf("expected override to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	http "net/http"
+
+	browserconfigv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	scheme "github.com/alcounit/browser-controller/pkg/browserconfig/clientset/scheme"
+	rest "k8s.io/client-go/rest"
+)
+
+type SelenosisV1Interface interface {
+	RESTClient() rest.Interface
+	BrowserConfigsGetter
+}
+
+// SelenosisV1Client is used to interact with features provided by the selenosis.io group.
+type SelenosisV1Client struct {
+	restClient rest.Interface
+}
+
+func (c *SelenosisV1Client) BrowserConfigs(namespace string) BrowserConfigInterface {
+	return newBrowserConfigs(c, namespace)
+}
+
+// NewForConfig creates a new SelenosisV1Client for the given config.
+// NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
+// where httpClient was generated with rest.HTTPClientFor(c).
+func NewForConfig(c *rest.Config) (*SelenosisV1Client, error) {
+	config := *c
+	setConfigDefaults(&config)
+	httpClient, err := rest.HTTPClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+	return NewForConfigAndClient(&config, httpClient)
+}
+
+// NewForConfigAndClient creates a new SelenosisV1Client for the given config and http client.
+// Note the http client provided takes precedence over the configured transport values.
+func NewForConfigAndClient(c *rest.Config, h *http.Client) (*SelenosisV1Client, error) {
+	config := *c
+	setConfigDefaults(&config)
+	client, err := rest.RESTClientForConfigAndClient(&config, h)
+	if err != nil {
+		return nil, err
+	}
+	return &SelenosisV1Client{client}, nil
+}
+
+// NewForConfigOrDie creates a new SelenosisV1Client for the given config and
+// panics if there is an error in the config.
+func NewForConfigOrDie(c *rest.Config) *SelenosisV1Client {
+	client, err := NewForConfig(c)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// New creates a new SelenosisV1Client for the given RESTClient.
+func New(c rest.Interface) *SelenosisV1Client {
+	return &SelenosisV1Client{c}
+}
+
+func setConfigDefaults(config *rest.Config) {
+	gv := browserconfigv1.SchemeGroupVersion
+	config.GroupVersion = &gv
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = rest.CodecFactoryForGeneratedClient(scheme.Scheme, scheme.Codecs).WithoutConversion()
+
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *SelenosisV1Client) RESTClient() rest.Interface {
+	if c == nil {
+		return nil
+	}
+	return c.restClient
+}
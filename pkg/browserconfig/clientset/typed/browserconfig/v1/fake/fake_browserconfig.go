@@ -0,0 +1,49 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	browserconfigv1 "github.com/alcounit/browser-controller/pkg/browserconfig/clientset/typed/browserconfig/v1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeBrowserConfigs implements BrowserConfigInterface
+type fakeBrowserConfigs struct {
+	*gentype.FakeClientWithList[*v1.BrowserConfig, *v1.BrowserConfigList]
+	Fake *FakeSelenosisV1
+}
+
+func newFakeBrowserConfigs(fake *FakeSelenosisV1, namespace string) browserconfigv1.BrowserConfigInterface {
+	return &fakeBrowserConfigs{
+		gentype.NewFakeClientWithList[*v1.BrowserConfig, *v1.BrowserConfigList](
+			fake.Fake,
+			namespace,
+			v1.SchemeGroupVersion.WithResource("browserconfigs"),
+			v1.SchemeGroupVersion.WithKind("BrowserConfig"),
+			func() *v1.BrowserConfig { return &v1.BrowserConfig{} },
+			func() *v1.BrowserConfigList { return &v1.BrowserConfigList{} },
+			func(dst, src *v1.BrowserConfigList) { dst.ListMeta = src.ListMeta },
+			func(list *v1.BrowserConfigList) []*v1.BrowserConfig { return gentype.ToPointerSlice(list.Items) },
+			func(list *v1.BrowserConfigList, items []*v1.BrowserConfig) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
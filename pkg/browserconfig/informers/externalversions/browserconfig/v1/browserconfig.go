@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	time "time"
+
+	apisbrowserconfigv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+	clientset "github.com/alcounit/browser-controller/pkg/browserconfig/clientset"
+	internalinterfaces "github.com/alcounit/browser-controller/pkg/browserconfig/informers/externalversions/internalinterfaces"
+	browserconfigv1 "github.com/alcounit/browser-controller/pkg/browserconfig/listers/browserconfig/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// BrowserConfigInformer provides access to a shared informer and lister for
+// BrowserConfigs.
+type BrowserConfigInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() browserconfigv1.BrowserConfigLister
+}
+
+type browserConfigInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewBrowserConfigInformer constructs a new informer for BrowserConfig type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewBrowserConfigInformer(client clientset.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredBrowserConfigInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredBrowserConfigInformer constructs a new informer for BrowserConfig type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredBrowserConfigInformer(client clientset.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SelenosisV1().BrowserConfigs(namespace).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SelenosisV1().BrowserConfigs(namespace).Watch(context.Background(), options)
+			},
+			ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SelenosisV1().BrowserConfigs(namespace).List(ctx, options)
+			},
+			WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SelenosisV1().BrowserConfigs(namespace).Watch(ctx, options)
+			},
+		}, client),
+		&apisbrowserconfigv1.BrowserConfig{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *browserConfigInformer) defaultInformer(client clientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredBrowserConfigInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *browserConfigInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&apisbrowserconfigv1.BrowserConfig{}, f.defaultInformer)
+}
+
+func (f *browserConfigInformer) Lister() browserconfigv1.BrowserConfigLister {
+	return browserconfigv1.NewBrowserConfigLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,100 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureSelfSigned(t *testing.T) {
+	dir := t.TempDir()
+	names := ServiceDNSNames("webhook", "default")
+
+	caPEM, err := EnsureSelfSigned(dir, names)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, CertFile), filepath.Join(dir, KeyFile))
+	if err != nil {
+		t.Fatalf("load key pair: %v", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caPEM)
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "webhook.default.svc", Roots: roots}); err != nil {
+		t.Fatalf("expected certificate to verify against the CA, got %v", err)
+	}
+
+	again, err := EnsureSelfSigned(dir, names)
+	if err != nil || !bytes.Equal(again, caPEM) {
+		t.Fatalf("expected existing certificates to be reused")
+	}
+
+	renamed, err := EnsureSelfSigned(dir, ServiceDNSNames("other", "default"))
+	if err != nil || bytes.Equal(renamed, caPEM) {
+		t.Fatalf("expected certificates to be regenerated for new DNS names")
+	}
+
+	info, err := os.Stat(filepath.Join(dir, KeyFile))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected private key to be written with 0600")
+	}
+}
+
+func TestEnsureSecret(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	key := client.ObjectKey{Namespace: "default", Name: "webhook-certs"}
+	names := ServiceDNSNames("webhook", "default")
+
+	// two replicas starting with empty certificate dirs share the certificate of the Secret
+	first, second := t.TempDir(), t.TempDir()
+	caPEM, err := EnsureSecret(context.Background(), c, key, first, names)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	again, err := EnsureSecret(context.Background(), c, key, second, names)
+	if err != nil || !bytes.Equal(again, caPEM) {
+		t.Fatalf("expected replicas to share the CA, got %v", err)
+	}
+	for _, name := range []string{CertFile, KeyFile} {
+		a, _ := os.ReadFile(filepath.Join(first, name))
+		b, _ := os.ReadFile(filepath.Join(second, name))
+		if len(a) == 0 || !bytes.Equal(a, b) {
+			t.Fatalf("expected replicas to serve the same %s", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(first, CAKeyFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected CA key not to be written to disk")
+	}
+
+	// a serving certificate that no longer covers the DNS names is renewed with the same CA
+	secret := &corev1.Secret{}
+	if err := c.Get(context.Background(), key, secret); err != nil {
+		t.Fatalf("get secret: %v", err)
+	}
+	oldCert := secret.Data[CertFile]
+	renamed := ServiceDNSNames("other", "default")
+	renewed, err := EnsureSecret(context.Background(), c, key, first, renamed)
+	if err != nil || !bytes.Equal(renewed, caPEM) {
+		t.Fatalf("expected certificate to be renewed with the same CA, got %v", err)
+	}
+	if err := c.Get(context.Background(), key, secret); err != nil {
+		t.Fatalf("get secret: %v", err)
+	}
+	if bytes.Equal(secret.Data[CertFile], oldCert) || !valid(caPEM, secret.Data[CertFile], renamed) {
+		t.Fatalf("expected renewed serving certificate in the Secret")
+	}
+}
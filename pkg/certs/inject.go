@@ -0,0 +1,61 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;patch
+
+// InjectCABundle sets caBundle on every webhook of the named validating and mutating
+// webhook configurations. Configurations that do not exist are skipped.
+func InjectCABundle(ctx context.Context, c client.Client, name string, caBundle []byte) error {
+	validating := &admissionv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, validating); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("get ValidatingWebhookConfiguration %s: %w", name, err)
+		}
+	} else {
+		patch := client.MergeFrom(validating.DeepCopy())
+		changed := false
+		for i := range validating.Webhooks {
+			if !bytes.Equal(validating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				validating.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if changed {
+			if err := c.Patch(ctx, validating, patch); err != nil {
+				return fmt.Errorf("patch ValidatingWebhookConfiguration %s: %w", name, err)
+			}
+		}
+	}
+
+	mutating := &admissionv1.MutatingWebhookConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, mutating); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("get MutatingWebhookConfiguration %s: %w", name, err)
+		}
+		return nil
+	}
+	patch := client.MergeFrom(mutating.DeepCopy())
+	changed := false
+	for i := range mutating.Webhooks {
+		if !bytes.Equal(mutating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			mutating.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := c.Patch(ctx, mutating, patch); err != nil {
+		return fmt.Errorf("patch MutatingWebhookConfiguration %s: %w", name, err)
+	}
+	return nil
+}
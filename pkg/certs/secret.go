@@ -0,0 +1,85 @@
+package certs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",namespace=default,resources=secrets,verbs=get;create;update
+
+// CAKeyFile is the key of the CA private key in the certificate Secret, it is never written to disk.
+const CAKeyFile = "ca.key"
+
+// secretAttempts bounds the retries when other replicas store their certificate at the same time.
+const secretAttempts = 3
+
+// EnsureSecret makes sure the Secret holds a serving certificate for dnsNames signed by a local CA,
+// writes it to dir and returns the PEM encoded CA certificate. Every replica loads the same certificate
+// from the Secret, the first one to start creates it. A serving certificate about to expire is renewed
+// with the CA of the Secret while it is valid, so the CA bundle injected into the webhooks keeps working.
+func EnsureSecret(ctx context.Context, c client.Client, key client.ObjectKey, dir string, dnsNames []string) ([]byte, error) {
+	if len(dnsNames) == 0 {
+		return nil, errors.New("at least one DNS name is required")
+	}
+
+	for attempt := 1; ; attempt++ {
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, key, secret)
+		exists := err == nil
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("get Secret %s: %w", key, err)
+		}
+
+		caPEM, caKeyPEM := secret.Data[CAFile], secret.Data[CAKeyFile]
+		certPEM, keyPEM := secret.Data[CertFile], secret.Data[KeyFile]
+		if exists && len(keyPEM) > 0 && valid(caPEM, certPEM, dnsNames) {
+			if err := writeFiles(dir, caPEM, certPEM, keyPEM); err != nil {
+				return nil, err
+			}
+			return caPEM, nil
+		}
+
+		if !validCA(caPEM, caKeyPEM) {
+			if caPEM, caKeyPEM, err = newCA(); err != nil {
+				return nil, err
+			}
+		}
+		if certPEM, keyPEM, err = issue(caPEM, caKeyPEM, dnsNames); err != nil {
+			return nil, err
+		}
+
+		secret.Data = map[string][]byte{CAFile: caPEM, CAKeyFile: caKeyPEM, CertFile: certPEM, KeyFile: keyPEM}
+		if exists {
+			err = c.Update(ctx, secret)
+		} else {
+			secret.ObjectMeta = metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}
+			secret.Type = corev1.SecretTypeOpaque
+			err = c.Create(ctx, secret)
+		}
+		switch {
+		case err == nil:
+			if err := writeFiles(dir, caPEM, certPEM, keyPEM); err != nil {
+				return nil, err
+			}
+			return caPEM, nil
+		case (apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)) && attempt < secretAttempts:
+			// another replica stored its certificate first, use that one
+			continue
+		default:
+			return nil, fmt.Errorf("store Secret %s: %w", key, err)
+		}
+	}
+}
+
+// validCA reports whether the CA and its key can sign a serving certificate outliving the renewal window.
+func validCA(caPEM, caKeyPEM []byte) bool {
+	caCert, caKey, err := parseCA(caPEM, caKeyPEM)
+	return err == nil && caCert.IsCA && caKey.PublicKey.Equal(caCert.PublicKey) && time.Until(caCert.NotAfter) > certValidity
+}
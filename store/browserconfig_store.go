@@ -3,11 +3,16 @@ package store
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	kcache "k8s.io/client-go/tools/cache"
 	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -15,18 +20,34 @@ import (
 
 // BrowserConfigStore keeps an in-memory cache of BrowserVersionConfig objects.
 type BrowserConfigStore struct {
-	mu     sync.RWMutex
-	config map[string]*configv1.BrowserVersionConfigSpec // key = namespace/browser:version
-	cache  crcache.Cache
-	log    logr.Logger
+	mu      sync.RWMutex
+	config  map[string]*configv1.BrowserVersionConfigSpec // key = namespace/browser:version
+	sources map[types.NamespacedName]*configSource        // keys owned by each BrowserConfig
+	cache   crcache.Cache
+	log     logr.Logger
+	synced  atomic.Bool
+
+	// defaultNamespace holds cluster-wide BrowserConfigs used when a namespace has no matching entry
+	defaultNamespace string
+	// namespaces limits the store to the namespaces the manager watches, all when empty
+	namespaces map[string]struct{}
 }
 
 func NewBrowserConfigStore() *BrowserConfigStore {
 	return &BrowserConfigStore{
-		config: make(map[string]*configv1.BrowserVersionConfigSpec),
+		config:  make(map[string]*configv1.BrowserVersionConfigSpec),
+		sources: make(map[types.NamespacedName]*configSource),
 	}
 }
 
+// configSource holds the merged entries defined by a single BrowserConfig.
+type configSource struct {
+	name    types.NamespacedName
+	created metav1.Time
+	specs   map[string]*configv1.BrowserVersionConfigSpec // key = namespace/browser:version
+	quota   *configv1.BrowserQuota
+}
+
 // WithCache injects the controller-runtime cache and logger into the store.
 func (s *BrowserConfigStore) WithCache(c crcache.Cache, log logr.Logger) manager.Runnable {
 	s.cache = c
@@ -34,6 +55,36 @@ func (s *BrowserConfigStore) WithCache(c crcache.Cache, log logr.Logger) manager
 	return s
 }
 
+// WithDefaultNamespace designates the namespace whose BrowserConfigs act as cluster-wide defaults.
+// An empty namespace disables the fallback.
+func (s *BrowserConfigStore) WithDefaultNamespace(namespace string) *BrowserConfigStore {
+	s.defaultNamespace = namespace
+	return s
+}
+
+// WithNamespaces limits the store to the namespaces the manager watches. BrowserConfigs of other
+// namespaces, except the default namespace, are ignored. No namespaces means all namespaces.
+func (s *BrowserConfigStore) WithNamespaces(namespaces ...string) *BrowserConfigStore {
+	s.namespaces = nil
+	if len(namespaces) == 0 {
+		return s
+	}
+	s.namespaces = make(map[string]struct{}, len(namespaces))
+	for _, ns := range namespaces {
+		s.namespaces[ns] = struct{}{}
+	}
+	return s
+}
+
+// Watches reports whether Browsers of the namespace are managed by this controller.
+func (s *BrowserConfigStore) Watches(namespace string) bool {
+	if len(s.namespaces) == 0 {
+		return true
+	}
+	_, ok := s.namespaces[namespace]
+	return ok
+}
+
 // keyFor builds the unique cache key for a browser config.
 func keyFor(namespace, browser, version string) string {
 	return fmt.Sprintf("%s/%s:%s", namespace, strings.ToLower(browser), strings.ToLower(version))
@@ -50,22 +101,44 @@ func (s *BrowserConfigStore) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to get informer for BrowserConfig: %w", err)
 	}
 
-	informer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
+	registration, err := informer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj any) { s.onAddOrUpdate(obj, s.log) },
 		UpdateFunc: func(_, newObj any) { s.onAddOrUpdate(newObj, s.log) },
 		DeleteFunc: func(obj any) { s.onDelete(obj, s.log) },
 	})
+	if err != nil {
+		return fmt.Errorf("failed to add BrowserConfig event handler: %w", err)
+	}
 
-	// Wait until cache is synced
-	if !kcache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+	// Wait until cache is synced and the initial list has been handled
+	if !kcache.WaitForCacheSync(ctx.Done(), informer.HasSynced, registration.HasSynced) {
 		return fmt.Errorf("failed to sync BrowserConfig informer cache")
 	}
+	s.synced.Store(true)
 
 	s.log.Info("BrowserConfigStore successfully started and synced")
 	<-ctx.Done()
 	return nil
 }
 
+// HasSynced reports whether the store holds every BrowserConfig known at startup.
+func (s *BrowserConfigStore) HasSynced() bool {
+	return s.synced.Load()
+}
+
+// ReadyCheck is a healthz checker failing until the store has synced.
+func (s *BrowserConfigStore) ReadyCheck(_ *http.Request) error {
+	if !s.HasSynced() {
+		return fmt.Errorf("BrowserConfigStore not synced")
+	}
+	return nil
+}
+
+// Add loads a BrowserConfig into the store without an informer, e.g. to resolve versions offline.
+func (s *BrowserConfigStore) Add(bc *configv1.BrowserConfig) {
+	s.onAddOrUpdate(bc, s.log)
+}
+
 func (s *BrowserConfigStore) onAddOrUpdate(obj any, log logr.Logger) {
 	var bc *configv1.BrowserConfig
 	switch t := obj.(type) {
@@ -82,20 +155,44 @@ func (s *BrowserConfigStore) onAddOrUpdate(obj any, log logr.Logger) {
 	if bc == nil {
 		return
 	}
-
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	if !s.Watches(bc.Namespace) && bc.Namespace != s.defaultNamespace {
+		log.V(1).Info("BrowserConfig ignored, namespace not watched", "namespace", bc.Namespace, "name", bc.Name)
+		return
+	}
 
 	bcCopy := bc.DeepCopy()
 	bcCopy.Spec.MergeWithTemplate()
 
+	src := &configSource{
+		name:    types.NamespacedName{Namespace: bcCopy.Namespace, Name: bcCopy.Name},
+		created: bcCopy.CreationTimestamp,
+		specs:   map[string]*configv1.BrowserVersionConfigSpec{},
+		quota:   bcCopy.Spec.Quota,
+	}
 	for browserName, versions := range bcCopy.Spec.Browsers {
 		for version, cfg := range versions {
-			key := keyFor(bcCopy.Namespace, browserName, version)
-			s.config[key] = cfg
-			log.Info("BrowserConfig added/updated", "key", key)
+			src.specs[keyFor(bcCopy.Namespace, browserName, version)] = cfg
 		}
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// refresh keys of the previous generation too, so removed versions are evicted
+	affected := map[string]struct{}{}
+	if old, ok := s.sources[src.name]; ok {
+		for key := range old.specs {
+			affected[key] = struct{}{}
+		}
+	}
+	for key := range src.specs {
+		affected[key] = struct{}{}
+	}
+
+	s.sources[src.name] = src
+	for key := range affected {
+		s.refreshKey(key, log)
+	}
 }
 
 func (s *BrowserConfigStore) onDelete(obj any, log logr.Logger) {
@@ -118,19 +215,186 @@ func (s *BrowserConfigStore) onDelete(obj any, log logr.Logger) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	name := types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name}
+	affected := map[string]struct{}{}
+	if old, ok := s.sources[name]; ok {
+		for key := range old.specs {
+			affected[key] = struct{}{}
+		}
+	}
 	for browserName, versions := range bc.Spec.Browsers {
 		for version := range versions {
-			key := keyFor(bc.Namespace, browserName, version)
+			affected[keyFor(bc.Namespace, browserName, version)] = struct{}{}
+		}
+	}
+
+	delete(s.sources, name)
+	for key := range affected {
+		s.refreshKey(key, log)
+	}
+}
+
+// refreshKey recomputes the served entry for a key from the BrowserConfigs defining it,
+// callers must hold the write lock. When several BrowserConfigs define the same key
+// the oldest one wins, ties are broken by name.
+func (s *BrowserConfigStore) refreshKey(key string, log logr.Logger) {
+	owners := s.ownersOf(key)
+	if len(owners) == 0 {
+		if _, ok := s.config[key]; ok {
 			delete(s.config, key)
 			log.Info("BrowserConfig deleted", "key", key)
 		}
+		return
+	}
+
+	s.config[key] = owners[0].specs[key]
+	log.Info("BrowserConfig added/updated", "key", key, "owner", owners[0].name.Name)
+
+	if len(owners) > 1 {
+		names := make([]string, 0, len(owners)-1)
+		for _, o := range owners[1:] {
+			names = append(names, o.name.Name)
+		}
+		log.Info("BrowserConfig conflict, entry defined by several BrowserConfigs",
+			"key", key, "owner", owners[0].name.Name, "ignored", names)
 	}
 }
 
-// Get retrieves BrowserVersionConfig from the in-memory store.
+// ownersOf returns the BrowserConfigs defining key, winner first.
+func (s *BrowserConfigStore) ownersOf(key string) []*configSource {
+	var owners []*configSource
+	for _, src := range s.sources {
+		if _, ok := src.specs[key]; ok {
+			owners = append(owners, src)
+		}
+	}
+
+	sort.Slice(owners, func(i, j int) bool {
+		a, b := owners[i], owners[j]
+		if !a.created.Equal(&b.created) {
+			return a.created.Before(&b.created)
+		}
+		return a.name.Name < b.name.Name
+	})
+	return owners
+}
+
+// Get retrieves BrowserVersionConfig from the in-memory store, resolving version queries, see Resolve.
 func (s *BrowserConfigStore) Get(namespace, browserName, version string) (*configv1.BrowserVersionConfigSpec, bool) {
+	cfg, _, exists := s.Resolve(namespace, browserName, version)
+	return cfg, exists
+}
+
+// Resolve retrieves BrowserVersionConfig together with the concrete version it is stored under.
+// An exact version match always wins, otherwise the version is treated as a query
+// ("latest", "120", "120.x", ">=118 <121") and the highest matching version is returned.
+// Namespaces without a matching entry fall back to the default namespace, see WithDefaultNamespace.
+func (s *BrowserConfigStore) Resolve(namespace, browserName, version string) (*configv1.BrowserVersionConfigSpec, string, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	cfg, exists := s.config[keyFor(namespace, browserName, version)]
-	return cfg, exists
+
+	if cfg, resolved, exists := s.resolveIn(namespace, browserName, version); exists {
+		return cfg, resolved, true
+	}
+	if s.defaultNamespace == "" || s.defaultNamespace == namespace {
+		return nil, "", false
+	}
+	return s.resolveIn(s.defaultNamespace, browserName, version)
+}
+
+// resolveIn resolves a version within a single namespace, callers must hold the lock.
+func (s *BrowserConfigStore) resolveIn(namespace, browserName, version string) (*configv1.BrowserVersionConfigSpec, string, bool) {
+	if cfg, exists := s.config[keyFor(namespace, browserName, version)]; exists {
+		return cfg, strings.ToLower(version), true
+	}
+
+	match, ok := parseVersionQuery(version)
+	if !ok {
+		return nil, "", false
+	}
+
+	prefix := keyFor(namespace, browserName, "")
+	resolved := ""
+	for key := range s.config {
+		candidate, found := strings.CutPrefix(key, prefix)
+		if !found || !match(candidate) {
+			continue
+		}
+		if resolved == "" || compareVersions(candidate, resolved) > 0 {
+			resolved = candidate
+		}
+	}
+
+	if resolved == "" {
+		return nil, "", false
+	}
+	return s.config[prefix+resolved], resolved, true
+}
+
+// Quota returns the quota of a namespace combined from its BrowserConfigs, the lowest limit
+// of each kind wins. Quotas are not inherited from the default namespace, nil means unlimited.
+func (s *BrowserConfigStore) Quota(namespace string) *configv1.BrowserQuota {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var quota *configv1.BrowserQuota
+	for name, src := range s.sources {
+		if name.Namespace != namespace || src.quota == nil {
+			continue
+		}
+		if quota == nil {
+			quota = &configv1.BrowserQuota{}
+		}
+		quota.MaxBrowsers = minLimit(quota.MaxBrowsers, src.quota.MaxBrowsers)
+		quota.MaxBrowsersPerVersion = minLimit(quota.MaxBrowsersPerVersion, src.quota.MaxBrowsersPerVersion)
+		quota.MaxBrowsersPerOwner = minLimit(quota.MaxBrowsersPerOwner, src.quota.MaxBrowsersPerOwner)
+		quota.MaxPriority = minLimit(quota.MaxPriority, src.quota.MaxPriority)
+	}
+	return quota
+}
+
+func minLimit(a, b *int32) *int32 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+// Entry is a snapshot of a single stored browser version config.
+type Entry struct {
+	Namespace   string
+	BrowserName string
+	Version     string
+	Config      *configv1.BrowserVersionConfigSpec
+}
+
+// Entries returns a snapshot of all stored browser version configs sorted by key.
+func (s *BrowserConfigStore) Entries() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.config))
+	for key := range s.config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		namespace, rest, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+		browserName, version, ok := strings.Cut(rest, ":")
+		if !ok {
+			continue
+		}
+		entries = append(entries, Entry{
+			Namespace:   namespace,
+			BrowserName: browserName,
+			Version:     version,
+			Config:      s.config[key],
+		})
+	}
+	return entries
 }
@@ -208,6 +208,25 @@ func TestBrowserConfigStoreGetMissing(t *testing.T) {
 	}
 }
 
+func TestBrowserConfigStoreEntries(t *testing.T) {
+	store := NewBrowserConfigStore()
+	chrome := &configv1.BrowserVersionConfigSpec{Image: "chrome"}
+	firefox := &configv1.BrowserVersionConfigSpec{Image: "firefox"}
+	store.config[keyFor("ns", "Firefox", "118.0")] = firefox
+	store.config[keyFor("ns", "Chrome", "120.0")] = chrome
+
+	entries := store.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Namespace != "ns" || entries[0].BrowserName != "chrome" || entries[0].Version != "120.0" || entries[0].Config != chrome {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].BrowserName != "firefox" || entries[1].Config != firefox {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
 func TestBrowserConfigStoreStartNoCache(t *testing.T) {
 	store := NewBrowserConfigStore()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -387,3 +406,156 @@ type fakeHandlerReg struct {
 func (r fakeHandlerReg) HasSynced() bool {
 	return r.synced
 }
+
+func TestBrowserConfigStoreDefaultNamespaceFallback(t *testing.T) {
+	store := NewBrowserConfigStore().WithDefaultNamespace("browser-system")
+	store.config[keyFor("browser-system", "chrome", "120.0")] = &configv1.BrowserVersionConfigSpec{Image: "default-120"}
+	store.config[keyFor("browser-system", "chrome", "121.0")] = &configv1.BrowserVersionConfigSpec{Image: "default-121"}
+	store.config[keyFor("team", "chrome", "120.0")] = &configv1.BrowserVersionConfigSpec{Image: "team-120"}
+
+	if cfg, ok := store.Get("team", "chrome", "120.0"); !ok || cfg.Image != "team-120" {
+		t.Fatalf("expected namespace-local entry to win, got %v", cfg)
+	}
+	if cfg, ok := store.Get("team", "chrome", "121.0"); !ok || cfg.Image != "default-121" {
+		t.Fatalf("expected fallback to default namespace, got %v", cfg)
+	}
+	if cfg, resolved, ok := store.Resolve("team", "chrome", "latest"); !ok || cfg.Image != "team-120" || resolved != "120.0" {
+		t.Fatalf("expected latest to resolve within the namespace first, got %v %q", cfg, resolved)
+	}
+	if cfg, ok := store.Get("other", "chrome", "latest"); !ok || cfg.Image != "default-121" {
+		t.Fatalf("expected latest to resolve from default namespace, got %v", cfg)
+	}
+	if _, ok := store.Get("team", "firefox", "118.0"); ok {
+		t.Fatalf("expected missing browser not to resolve")
+	}
+
+	store.WithDefaultNamespace("")
+	if _, ok := store.Get("other", "chrome", "120.0"); ok {
+		t.Fatalf("expected fallback to be disabled")
+	}
+}
+
+func TestBrowserConfigStoreOnAddOrUpdateEvictsRemovedVersions(t *testing.T) {
+	bc := &configv1.BrowserConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: "ns"},
+		Spec: configv1.BrowserConfigSpec{
+			Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{
+				"chrome": {"119.0": {Image: "old"}, "120.0": {Image: "img"}},
+			},
+		},
+	}
+
+	store := NewBrowserConfigStore()
+	store.onAddOrUpdate(bc, logr.Discard())
+
+	updated := bc.DeepCopy()
+	delete(updated.Spec.Browsers["chrome"], "119.0")
+	updated.Spec.Browsers["chrome"]["121.0"] = &configv1.BrowserVersionConfigSpec{Image: "new"}
+	store.onAddOrUpdate(updated, logr.Discard())
+
+	if _, ok := store.Get("ns", "chrome", "119.0"); ok {
+		t.Fatalf("expected removed version to be evicted")
+	}
+	if cfg, ok := store.Get("ns", "chrome", "121.0"); !ok || cfg.Image != "new" {
+		t.Fatalf("expected new version to be stored")
+	}
+	if _, ok := store.Get("ns", "chrome", "120.0"); !ok {
+		t.Fatalf("expected kept version to stay")
+	}
+}
+
+func TestBrowserConfigStoreConflictingConfigs(t *testing.T) {
+	older := &configv1.BrowserConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "b-older", Namespace: "ns", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+		Spec: configv1.BrowserConfigSpec{
+			Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{
+				"chrome": {"120.0": {Image: "older"}},
+			},
+		},
+	}
+	newer := &configv1.BrowserConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-newer", Namespace: "ns", CreationTimestamp: metav1.NewTime(time.Now())},
+		Spec: configv1.BrowserConfigSpec{
+			Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{
+				"chrome":  {"120.0": {Image: "newer"}},
+				"firefox": {"118.0": {Image: "firefox"}},
+			},
+		},
+	}
+
+	store := NewBrowserConfigStore()
+	store.onAddOrUpdate(older, logr.Discard())
+	store.onAddOrUpdate(newer, logr.Discard())
+
+	if cfg, ok := store.Get("ns", "chrome", "120.0"); !ok || cfg.Image != "older" {
+		t.Fatalf("expected oldest BrowserConfig to win, got %v", cfg)
+	}
+
+	// removing the winner promotes the remaining definition
+	store.onDelete(older, logr.Discard())
+	if cfg, ok := store.Get("ns", "chrome", "120.0"); !ok || cfg.Image != "newer" {
+		t.Fatalf("expected remaining BrowserConfig to take over, got %v", cfg)
+	}
+}
+
+func TestBrowserConfigStoreWatchedNamespaces(t *testing.T) {
+	store := NewBrowserConfigStore().WithDefaultNamespace("browser-system").WithNamespaces("team-a", "team-b")
+
+	for _, ns := range []string{"team-a", "browser-system", "team-c"} {
+		store.Add(&configv1.BrowserConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "cfg", Namespace: ns},
+			Spec: configv1.BrowserConfigSpec{
+				Browsers: map[string]map[string]*configv1.BrowserVersionConfigSpec{
+					"chrome": {"120.0": {Image: ns}},
+				},
+			},
+		})
+	}
+
+	if !store.Watches("team-b") || store.Watches("team-c") || store.Watches("browser-system") {
+		t.Fatalf("unexpected watched namespaces")
+	}
+	if cfg, ok := store.Get("team-a", "chrome", "120.0"); !ok || cfg.Image != "team-a" {
+		t.Fatalf("expected watched namespace entry, got %v", cfg)
+	}
+	if cfg, ok := store.Get("team-b", "chrome", "120.0"); !ok || cfg.Image != "browser-system" {
+		t.Fatalf("expected fallback to the default namespace, got %v", cfg)
+	}
+	if len(store.Entries()) != 2 {
+		t.Fatalf("expected BrowserConfig of unwatched namespace to be ignored, got %v", store.Entries())
+	}
+
+	store.WithNamespaces()
+	if !store.Watches("team-c") {
+		t.Fatalf("expected all namespaces to be watched without a namespace list")
+	}
+}
+
+func TestBrowserConfigStoreQuota(t *testing.T) {
+	limit := func(v int32) *int32 { return &v }
+	store := NewBrowserConfigStore().WithDefaultNamespace("browser-system")
+	for _, bc := range []*configv1.BrowserConfig{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"},
+			Spec:       configv1.BrowserConfigSpec{Quota: &configv1.BrowserQuota{MaxBrowsers: limit(10), MaxBrowsersPerOwner: limit(2)}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns"},
+			Spec:       configv1.BrowserConfigSpec{Quota: &configv1.BrowserQuota{MaxBrowsers: limit(5), MaxPriority: limit(10)}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "browser-system"},
+			Spec:       configv1.BrowserConfigSpec{Quota: &configv1.BrowserQuota{MaxBrowsers: limit(1)}},
+		},
+	} {
+		store.Add(bc)
+	}
+
+	quota := store.Quota("ns")
+	if quota == nil || *quota.MaxBrowsers != 5 || *quota.MaxBrowsersPerOwner != 2 || quota.MaxBrowsersPerVersion != nil || *quota.MaxPriority != 10 {
+		t.Fatalf("expected lowest limits to be combined, got %+v", quota)
+	}
+	if quota := store.Quota("other"); quota != nil {
+		t.Fatalf("expected quota not to be inherited from the default namespace, got %+v", quota)
+	}
+}
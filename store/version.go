@@ -0,0 +1,169 @@
+package store
+
+import (
+	"strconv"
+	"strings"
+)
+
+// versionLatest selects the highest configured version of a browser.
+const versionLatest = "latest"
+
+// versionMatcher reports whether a configured version satisfies a version query.
+type versionMatcher func(version string) bool
+
+// parseVersionQuery turns a requested browser version into a matcher.
+// Supported queries are:
+//   - "latest", any numeric version
+//   - "120" or "120.0", any version with that prefix ("120" matches "120.0.6099")
+//   - "120.x" or "120.*", same as "120"
+//   - ">=118 <121", space or comma separated comparisons using >=, >, <=, < and =
+//
+// Only versions starting with a digit take part in resolution, so "beta" or "dev"
+// entries are never picked unless requested by their exact name.
+func parseVersionQuery(query string) (versionMatcher, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, false
+	}
+
+	if query == versionLatest {
+		return isNumericVersion, true
+	}
+
+	if strings.ContainsAny(query, "<>=") {
+		return parseVersionRange(query)
+	}
+
+	segments := strings.Split(query, ".")
+	prefix := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		if segment == "x" || segment == "*" {
+			if i != len(segments)-1 {
+				return nil, false
+			}
+			break
+		}
+		if !isNumber(segment) {
+			return nil, false
+		}
+		prefix = append(prefix, segment)
+	}
+	if len(prefix) == 0 {
+		return isNumericVersion, true
+	}
+
+	return func(version string) bool {
+		if !isNumericVersion(version) {
+			return false
+		}
+		parts := strings.Split(version, ".")
+		if len(parts) < len(prefix) {
+			return false
+		}
+		for i := range prefix {
+			if compareSegment(parts[i], prefix[i]) != 0 {
+				return false
+			}
+		}
+		return true
+	}, true
+}
+
+func parseVersionRange(query string) (versionMatcher, bool) {
+	fields := strings.FieldsFunc(query, func(r rune) bool { return r == ' ' || r == ',' })
+	matchers := make([]versionMatcher, 0, len(fields))
+
+	for _, field := range fields {
+		op, bound := splitOperator(field)
+		if op == "" || bound == "" || !isNumericVersion(bound) {
+			return nil, false
+		}
+
+		matchers = append(matchers, func(version string) bool {
+			cmp := compareVersions(version, bound)
+			switch op {
+			case ">=":
+				return cmp >= 0
+			case ">":
+				return cmp > 0
+			case "<=":
+				return cmp <= 0
+			case "<":
+				return cmp < 0
+			default:
+				return cmp == 0
+			}
+		})
+	}
+
+	return func(version string) bool {
+		if !isNumericVersion(version) {
+			return false
+		}
+		for _, match := range matchers {
+			if !match(version) {
+				return false
+			}
+		}
+		return true
+	}, true
+}
+
+func splitOperator(field string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(field, op) {
+			return op, strings.TrimPrefix(field, op)
+		}
+	}
+	return "", ""
+}
+
+// compareVersions compares dotted versions segment by segment, numerically where possible.
+// Missing segments count as zero, so "120" and "120.0" are equal.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if cmp := compareSegment(x, y); cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+func compareSegment(a, b string) int {
+	x, errA := strconv.ParseUint(a, 10, 64)
+	y, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	case errA == nil:
+		// numeric segments sort above pre-release style segments
+		return 1
+	case errB == nil:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func isNumericVersion(version string) bool {
+	return version != "" && version[0] >= '0' && version[0] <= '9'
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"testing"
+
+	configv1 "github.com/alcounit/browser-controller/apis/browserconfig/v1"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"120.0", "120.0", 0},
+		{"120", "120.0", 0},
+		{"121.0", "120.9", 1},
+		{"120.10", "120.9", 1},
+		{"99.0", "100.0", -1},
+		{"120.0", "120.0-beta", 1},
+	}
+	for _, tc := range cases {
+		if got := compareVersions(tc.a, tc.b); got != tc.want {
+			t.Fatalf("compareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestParseVersionQuery(t *testing.T) {
+	cases := []struct {
+		query   string
+		version string
+		want    bool
+	}{
+		{"latest", "120.0", true},
+		{"LATEST", "beta", false},
+		{"120", "120.0", true},
+		{"120", "1200.0", false},
+		{"120.x", "120.1", true},
+		{"120.*", "121.0", false},
+		{"120.0", "120.0.6099", true},
+		{">=118 <121", "120.0", true},
+		{">=118,<121", "121.0", false},
+		{">118", "118.0", false},
+		{"=120", "120.0", true},
+	}
+	for _, tc := range cases {
+		match, ok := parseVersionQuery(tc.query)
+		if !ok {
+			t.Fatalf("expected query %q to parse", tc.query)
+		}
+		if got := match(tc.version); got != tc.want {
+			t.Fatalf("query %q on %q = %v, want %v", tc.query, tc.version, got, tc.want)
+		}
+	}
+
+	for _, query := range []string{"", "beta", "120.x.1", ">=", ">=beta"} {
+		if _, ok := parseVersionQuery(query); ok {
+			t.Fatalf("expected query %q to be rejected", query)
+		}
+	}
+}
+
+func TestBrowserConfigStoreResolve(t *testing.T) {
+	store := NewBrowserConfigStore()
+	store.config[keyFor("ns", "chrome", "119.0")] = &configv1.BrowserVersionConfigSpec{Image: "119.0"}
+	store.config[keyFor("ns", "chrome", "120.0")] = &configv1.BrowserVersionConfigSpec{Image: "120.0"}
+	store.config[keyFor("ns", "chrome", "120.1")] = &configv1.BrowserVersionConfigSpec{Image: "120.1"}
+	store.config[keyFor("ns", "chrome", "dev")] = &configv1.BrowserVersionConfigSpec{Image: "dev"}
+	store.config[keyFor("other", "chrome", "130.0")] = &configv1.BrowserVersionConfigSpec{Image: "other"}
+
+	cases := []struct {
+		version  string
+		resolved string
+	}{
+		{"120.0", "120.0"},
+		{"Dev", "dev"},
+		{"latest", "120.1"},
+		{"120", "120.1"},
+		{"<120", "119.0"},
+	}
+	for _, tc := range cases {
+		cfg, resolved, ok := store.Resolve("ns", "Chrome", tc.version)
+		if !ok {
+			t.Fatalf("expected %q to resolve", tc.version)
+		}
+		if resolved != tc.resolved || cfg.Image != tc.resolved {
+			t.Fatalf("expected %q to resolve to %q, got %q (%s)", tc.version, tc.resolved, resolved, cfg.Image)
+		}
+	}
+
+	if _, _, ok := store.Resolve("ns", "chrome", "121"); ok {
+		t.Fatalf("expected unmatched version not to resolve")
+	}
+	if _, _, ok := store.Resolve("ns", "firefox", "latest"); ok {
+		t.Fatalf("expected unknown browser not to resolve")
+	}
+	if cfg, ok := store.Get("ns", "chrome", "latest"); !ok || cfg.Image != "120.1" {
+		t.Fatalf("expected Get to resolve latest")
+	}
+}